@@ -0,0 +1,127 @@
+package nest
+
+import (
+	"bytes"
+)
+
+// StripANSI removes the ANSI escape sequences from the content written to the Writer
+// and from the titles of the new Writers, such as the colors of the output of a subprocess,
+// so that they are stored, and rendered by all the renderers, as plain text.
+func StripANSI() Option {
+	return func(n *Writer) {
+		n.opts.stripANSI = true
+	}
+}
+
+// escapeLen returns the length of the escape sequence b starts with, if any.
+// An unterminated sequence takes the rest of b.
+func escapeLen(b []byte) int {
+	if len(b) == 0 || b[0] != '\x1b' {
+		return 0
+	}
+	if len(b) == 1 {
+		return 1
+	}
+	switch b[1] {
+	case '[':
+		// Control Sequence: parameters and intermediate bytes, then a final byte.
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+			if b[i] < 0x20 || b[i] > 0x3f {
+				return i
+			}
+		}
+		return len(b)
+	case ']', 'P', '_', '^', 'X':
+		// Operating System Command and strings, terminated by BEL or by ESC \.
+		for i := 2; i < len(b); i++ {
+			if b[i] == '\a' {
+				return i + 1
+			}
+			if b[i] == '\x1b' && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(b)
+	default:
+		return 2
+	}
+}
+
+// stripEscapes returns p without its escape sequences, p itself if it has none.
+func stripEscapes(p []byte) []byte {
+	i := bytes.IndexByte(p, '\x1b')
+	if i < 0 {
+		return p
+	}
+	stripped := append([]byte(nil), p[:i]...)
+	for p = p[i:]; len(p) > 0; {
+		if l := escapeLen(p); l > 0 {
+			p = p[l:]
+			continue
+		}
+		i := bytes.IndexByte(p, '\x1b')
+		if i < 0 {
+			i = len(p)
+		}
+		stripped = append(stripped, p[:i]...)
+		p = p[i:]
+	}
+	return stripped
+}
+
+// confineStyles returns raw with the styles set by its escape sequences confined to each line:
+// a line leaving a style set is followed by a reset, and the style is set again
+// at the start of the following lines, so that it never bleeds into the indentation.
+// raw is returned as it is when it has no escape sequences.
+func confineStyles(raw []byte) []byte {
+	if bytes.IndexByte(raw, '\x1b') < 0 {
+		return raw
+	}
+
+	var confined, active []byte
+	for len(raw) > 0 {
+		line, end := raw, len(raw)
+		if i := bytes.IndexByte(raw, '\n'); i >= 0 {
+			line, end = raw[:i], i+1
+		}
+		if len(line) > 0 {
+			confined = append(confined, active...)
+		}
+		for b := line; len(b) > 0; {
+			l := escapeLen(b)
+			if l == 0 {
+				b = b[1:]
+				continue
+			}
+			active = sgr(active, b[:l])
+			b = b[l:]
+		}
+		confined = append(confined, line...)
+		if len(line) > 0 && len(active) > 0 {
+			confined = append(confined, "\x1b[0m"...)
+		}
+		confined = append(confined, raw[len(line):end]...)
+		raw = raw[end:]
+	}
+	return confined
+}
+
+// sgr returns the active styles once the escape sequence seq is applied,
+// which only changes them when it is a Select Graphic Rendition one.
+func sgr(active, seq []byte) []byte {
+	if len(seq) < 3 || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return active
+	}
+	params := seq[2 : len(seq)-1]
+	switch {
+	case len(params) == 0 || bytes.Equal(params, []byte("0")):
+		return active[:0]
+	case bytes.HasPrefix(params, []byte("0;")):
+		return append(active[:0], seq...)
+	default:
+		return append(active, seq...)
+	}
+}
@@ -0,0 +1,20 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleStripANSI() {
+	n := New(StripANSI())
+	build := WithTitledParent(n, []byte("go build"))
+	if _, err := build.WriteString("\x1b[31mFAIL\x1b[0m\tgithub.com/damianopetrungaro/nest"); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// go build
+	//     FAIL	github.com/damianopetrungaro/nest
+}
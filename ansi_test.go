@@ -0,0 +1,161 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEscapeLen(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want int
+	}{
+		"no escape":                {s: "text", want: 0},
+		"color":                    {s: "\x1b[31mred", want: 5},
+		"reset":                    {s: "\x1b[m", want: 3},
+		"256 colors":               {s: "\x1b[38;5;208mx", want: 11},
+		"cursor":                   {s: "\x1b[2K", want: 4},
+		"unterminated":             {s: "\x1b[31", want: 4},
+		"operating system":         {s: "\x1b]0;title\ax", want: 10},
+		"operating system with st": {s: "\x1b]8;;url\x1b\\x", want: 10},
+		"two bytes":                {s: "\x1b7x", want: 2},
+		"lone escape":              {s: "\x1b", want: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := escapeLen([]byte(test.s)); got != test.want {
+				t.Errorf("could not match length of %q: got %d, want %d", test.s, got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfineStyles(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want string
+	}{
+		"no escape": {
+			s:    "one\ntwo\n",
+			want: "one\ntwo\n",
+		},
+		"style reset on the line": {
+			s:    "\x1b[31mred\x1b[0m\nplain\n",
+			want: "\x1b[31mred\x1b[0m\nplain\n",
+		},
+		"style across lines": {
+			s:    "\x1b[31mone\ntwo\x1b[0m\nplain\n",
+			want: "\x1b[31mone\x1b[0m\n\x1b[31mtwo\x1b[0m\nplain\n",
+		},
+		"combined styles": {
+			s:    "\x1b[1m\x1b[31mone\n\ntwo\n",
+			want: "\x1b[1m\x1b[31mone\x1b[0m\n\n\x1b[1m\x1b[31mtwo\x1b[0m\n",
+		},
+		"style replaced after a reset": {
+			s:    "\x1b[1mone\x1b[0;32mtwo\nthree\n",
+			want: "\x1b[1mone\x1b[0;32mtwo\x1b[0m\n\x1b[0;32mthree\x1b[0m\n",
+		},
+		"not a style": {
+			s:    "\x1b[2Kone\ntwo\n",
+			want: "\x1b[2Kone\ntwo\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := string(confineStyles([]byte(test.s))); got != test.want {
+				t.Error("could not confine styles")
+				t.Errorf("got: %q", got)
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteTo_escapes(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		s    string
+		want string
+	}{
+		"style confined to the lines": {
+			s:    "\x1b[31merror\ndetails\x1b[0m",
+			want: "    \x1b[31merror\x1b[0m\n    \x1b[31mdetails\x1b[0m\n",
+		},
+		"width without escapes": {
+			opts: []Option{Wrap(14)},
+			s:    "\x1b[31mred words\x1b[0m here",
+			want: "    \x1b[31mred words\x1b[0m\n    here\n",
+		},
+		"wrapped style": {
+			opts: []Option{Wrap(14)},
+			s:    "\x1b[31mred words here\x1b[0m",
+			want: "    \x1b[31mred words\x1b[0m\n    \x1b[31mhere\x1b[0m\n",
+		},
+		"stripped": {
+			opts: []Option{StripANSI()},
+			s:    "\x1b[31merror\x1b[0m\n\x1b]0;title\adetails",
+			want: "    error\n    details\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := WithParent(New(test.opts...))
+			if _, err := n.WriteString(test.s); err != nil {
+				t.Fatalf("could not write: %s", err)
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteTo_titleEscapes(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		want string
+	}{
+		"confined title": {
+			want: "\x1b[31mred\x1b[0m\n\x1b[31msecond\x1b[0m\n    content\n",
+		},
+		"stripped title": {
+			opts: []Option{StripANSI()},
+			want: "red\nsecond\n    content\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := New(test.opts...)
+			_, _ = WithTitledParent(n, []byte("\x1b[31mred\nsecond")).WriteString("content")
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match title written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	n := New(StripANSI(), LineBuffered())
+	_, _ = n.WriteString("\x1b[3")
+	_, _ = n.WriteString("1mred\x1b[0m\n")
+
+	if got, want := n.Buf.String(), "red\n"; got != want {
+		t.Errorf("could not strip a sequence written in chunks: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package nest
+
+import (
+	"html"
+	"io"
+)
+
+// htmlStyle is the CSS embedded in standalone HTML pages.
+const htmlStyle = `body{font-family:sans-serif}
+.nest pre{margin:0;font-family:monospace}
+.nest summary{cursor:pointer;font-weight:bold}
+.nest-section{margin-left:1.5em}`
+
+// An HTML renders a Writer as HTML, each titled child being a collapsible <details> element
+// whose <summary> is the title.
+// Lines written to a Writer are rendered in a <pre> element, and untitled children
+// in a plain <div>; all the content is escaped.
+// Titled children deeper than CollapseDepth start collapsed,
+// a zero CollapseDepth renders them all expanded.
+// When Standalone is set the output is a complete page titled Title, with minimal embedded CSS.
+type HTML struct {
+	Writer        *Writer
+	CollapseDepth int
+	Standalone    bool
+	Title         string
+}
+
+// WriteTo writes the HTML to w without consuming the content of the Writer.
+// The return value n is the number of bytes written; it always fits into an
+// int, but it is int64 to match the io.WriterTo interface. Any error
+// encountered during the write is also returned.
+func (h HTML) WriteTo(w io.Writer) (n int64, err error) {
+	p := h.Writer.printer(w)
+	if h.Standalone {
+		p.line([]byte("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">"))
+		p.line([]byte("<title>"), []byte(html.EscapeString(h.Title)), []byte("</title>"))
+		p.line([]byte("<style>\n" + htmlStyle + "\n</style>\n</head>\n<body>"))
+	}
+
+	p.line([]byte(`<div class="nest">`))
+	if v := h.Writer.view(); len(v.title) > 0 {
+		h.details(p, v, 1)
+	} else {
+		h.content(p, v, 1)
+	}
+	p.line([]byte("</div>"))
+
+	if h.Standalone {
+		p.line([]byte("</body>\n</html>"))
+	}
+	return p.n, p.err
+}
+
+// content renders the lines and the children of v, whose titled children are at the given level.
+func (h HTML) content(p *printer, v *view, level int) {
+	if lines := v.lines(); len(lines) > 0 {
+		p.write([]byte("<pre>"))
+		for i, line := range lines {
+			if i > 0 {
+				p.write([]byte{'\n'})
+			}
+			p.write([]byte(html.EscapeString(string(line))))
+		}
+		p.line([]byte("</pre>"))
+	}
+
+	for _, child := range v.children {
+		if len(child.title) > 0 {
+			h.details(p, child, level)
+			continue
+		}
+		p.line([]byte(`<div class="nest-section">`))
+		h.content(p, child, level)
+		p.line([]byte("</div>"))
+	}
+}
+
+// details renders the titled v, at the given level, as a <details> element.
+func (h HTML) details(p *printer, v *view, level int) {
+	if h.CollapseDepth <= 0 || level <= h.CollapseDepth {
+		p.write([]byte("<details open>\n<summary>"))
+	} else {
+		p.write([]byte("<details>\n<summary>"))
+	}
+	for i, title := range v.titleLines() {
+		if i > 0 {
+			p.write([]byte("<br>"))
+		}
+		p.write([]byte(html.EscapeString(string(title))))
+	}
+	p.line([]byte("</summary>"))
+	p.line([]byte(`<div class="nest-section">`))
+	h.content(p, v, level+1)
+	p.line([]byte("</div>\n</details>"))
+}
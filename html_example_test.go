@@ -0,0 +1,64 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleHTML() {
+	base := NewSimpleWriter()
+
+	tests := base.Child("Tests")
+	tests.Write("2 passed, 1 failed")
+	failed := tests.Child("TestParse <failed>")
+	failed.Write("got: a & b\nwant: a")
+
+	if _, err := (HTML{Writer: base.Writer, CollapseDepth: 1}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// <div class="nest">
+	// <details open>
+	// <summary>Tests</summary>
+	// <div class="nest-section">
+	// <pre>2 passed, 1 failed</pre>
+	// <details>
+	// <summary>TestParse &lt;failed&gt;</summary>
+	// <div class="nest-section">
+	// <pre>got: a &amp; b
+	// want: a</pre>
+	// </div>
+	// </details>
+	// </div>
+	// </details>
+	// </div>
+}
+
+func ExampleHTML_standalone() {
+	base := New()
+	if _, err := base.WriteString("content"); err != nil {
+		panic(err)
+	}
+
+	if _, err := (HTML{Writer: base, Standalone: true, Title: "Report"}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// <!DOCTYPE html>
+	// <html>
+	// <head>
+	// <meta charset="utf-8">
+	// <title>Report</title>
+	// <style>
+	// body{font-family:sans-serif}
+	// .nest pre{margin:0;font-family:monospace}
+	// .nest summary{cursor:pointer;font-weight:bold}
+	// .nest-section{margin-left:1.5em}
+	// </style>
+	// </head>
+	// <body>
+	// <div class="nest">
+	// <pre>content</pre>
+	// </div>
+	// </body>
+	// </html>
+}
@@ -0,0 +1,131 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHTML_WriteTo(t *testing.T) {
+	n := New()
+	a := WithTitledParent(n, []byte("a\n<b>"))
+	_, _ = WithParent(a).WriteString("untitled")
+	_, _ = WithTitledParent(WithTitledParent(a, []byte("c")), []byte("d")).WriteString("\"quoted\"")
+
+	tests := map[string]struct {
+		html HTML
+		want string
+	}{
+		"expanded": {
+			html: HTML{Writer: n},
+			want: `<div class="nest">
+<details open>
+<summary>a<br>&lt;b&gt;</summary>
+<div class="nest-section">
+<div class="nest-section">
+<pre>untitled</pre>
+</div>
+<details open>
+<summary>c</summary>
+<div class="nest-section">
+<details open>
+<summary>d</summary>
+<div class="nest-section">
+<pre>&#34;quoted&#34;</pre>
+</div>
+</details>
+</div>
+</details>
+</div>
+</details>
+</div>
+`,
+		},
+		"titled writer without content": {
+			html: HTML{Writer: WithTitledParent(New(), []byte("e"))},
+			want: `<div class="nest">
+<details open>
+<summary>e</summary>
+<div class="nest-section">
+</div>
+</details>
+</div>
+`,
+		},
+		"untitled writer without content": {
+			html: HTML{Writer: WithParent(New())},
+			want: `<div class="nest">
+</div>
+`,
+		},
+		"titled writer collapsed beyond the second level": {
+			html: HTML{Writer: a, CollapseDepth: 2},
+			want: `<div class="nest">
+<details open>
+<summary>a<br>&lt;b&gt;</summary>
+<div class="nest-section">
+<div class="nest-section">
+<pre>untitled</pre>
+</div>
+<details open>
+<summary>c</summary>
+<div class="nest-section">
+<details>
+<summary>d</summary>
+<div class="nest-section">
+<pre>&#34;quoted&#34;</pre>
+</div>
+</details>
+</div>
+</details>
+</div>
+</details>
+</div>
+`,
+		},
+		"titled writer collapsed beyond the first level": {
+			html: HTML{Writer: a, CollapseDepth: 1},
+			want: `<div class="nest">
+<details open>
+<summary>a<br>&lt;b&gt;</summary>
+<div class="nest-section">
+<div class="nest-section">
+<pre>untitled</pre>
+</div>
+<details>
+<summary>c</summary>
+<div class="nest-section">
+<details>
+<summary>d</summary>
+<div class="nest-section">
+<pre>&#34;quoted&#34;</pre>
+</div>
+</details>
+</div>
+</details>
+</div>
+</details>
+</div>
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			i, err := test.html.WriteTo(w)
+			if err != nil {
+				t.Errorf("could not write html to the writer: %s", err)
+			}
+			if i != int64(len(test.want)) {
+				t.Error("could not match written bytes")
+				t.Errorf("got: %d", i)
+				t.Errorf("want: %d", len(test.want))
+			}
+			if w.String() != test.want {
+				t.Error("could not match html written")
+				t.Errorf("got: %s", w.String())
+				t.Errorf("want: %s", test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package nest
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonWriter is the JSON representation of a Writer.
+type jsonWriter struct {
+	Title    string       `json:"title,omitempty"`
+	Empty    bool         `json:"empty,omitempty"`
+	Lines    []string     `json:"lines"`
+	Children []jsonWriter `json:"children"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Each Writer is encoded as an object holding its title, the lines written to it
+// without indentation, and the children in order:
+//
+//	{"title":"Section 1","lines":["content"],"children":[]}
+//
+// The title is omitted when the Writer has none, and an empty Writer
+// whose title is kept by MarkEmpty is flagged with "empty":true.
+func (n *Writer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.view().toJSON())
+}
+
+func (v *view) toJSON() jsonWriter {
+	lines := v.lines()
+	j := jsonWriter{
+		Title:    string(bytes.Join(v.title, []byte{'\n'})),
+		Empty:    v.empty,
+		Lines:    make([]string, 0, len(lines)),
+		Children: make([]jsonWriter, 0, len(v.children)),
+	}
+	for _, line := range lines {
+		j.Lines = append(j.Lines, string(line))
+	}
+	for _, child := range v.children {
+		j.Children = append(j.Children, child.toJSON())
+	}
+	return j
+}
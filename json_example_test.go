@@ -0,0 +1,24 @@
+package nest
+
+import (
+	"encoding/json"
+	"os"
+)
+
+func ExampleWriter_MarshalJSON() {
+	base := New()
+	if _, err := base.WriteString("Report"); err != nil {
+		panic(err)
+	}
+
+	section := WithTitledParent(base, []byte("Section 1"))
+	if _, err := section.WriteString("line one\nline two"); err != nil {
+		panic(err)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(base); err != nil {
+		panic(err)
+	}
+	// Output:
+	// {"lines":["Report"],"children":[{"title":"Section 1","lines":["line one","line two"],"children":[]}]}
+}
@@ -0,0 +1,45 @@
+package nest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWriter_MarshalJSON(t *testing.T) {
+	nested := New(Indent("\t"))
+	_, _ = WithTitledParent(WithParent(nested), []byte("deep")).WriteString("\tkept tab")
+	marked := New(MarkEmpty("(empty)"))
+	WithTitledParent(marked, []byte("none"))
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"empty writer": {
+			nest: New(),
+			want: `{"lines":[],"children":[]}`,
+		},
+		"untitled and titled children with custom indentation": {
+			nest: nested,
+			want: `{"lines":[],"children":[{"lines":[],"children":[{"title":"deep","lines":["\tkept tab"],"children":[]}]}]}`,
+		},
+		"empty titled child with MarkEmpty": {
+			nest: marked,
+			want: `{"lines":[],"children":[{"title":"none","empty":true,"lines":[],"children":[]}]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(test.nest)
+			if err != nil {
+				t.Errorf("could not marshal the writer: %s", err)
+			}
+			if string(got) != test.want {
+				t.Error("could not match JSON")
+				t.Errorf("got: %s", got)
+				t.Errorf("want: %s", test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package nest
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// LimitLines keeps only the first head and the last tail lines written to each Writer,
+// the lines in between being replaced by a single one telling how many were omitted,
+// such as "… 4,312 lines omitted …".
+// The lines are dropped when they are written, so a noisy Writer holds a bounded amount of memory.
+// Given to WithParent or WithTitledParent, it limits a single section and its children.
+// Streaming Writers write all their lines through regardless.
+func LimitLines(head, tail int) Option {
+	return limitOption(false, head, tail)
+}
+
+// LimitBytes works like LimitLines, but it keeps the first lines fitting in head bytes
+// and the last lines fitting in tail bytes, counting the new line ending each of them,
+// so that a few very long lines are bounded as well.
+// A line longer than the limit is omitted as a whole.
+func LimitBytes(head, tail int) Option {
+	return limitOption(true, head, tail)
+}
+
+// limitOption returns the option limiting the lines, or the bytes, kept by each Writer.
+func limitOption(inBytes bool, head, tail int) Option {
+	if head < 0 {
+		head = 0
+	}
+	if tail < 0 {
+		tail = 0
+	}
+	return func(n *Writer) {
+		n.opts.limited = true
+		n.opts.limitBytes = inBytes
+		n.opts.limitHead = head
+		n.opts.limitTail = tail
+	}
+}
+
+// size returns the part of the limits a line takes, that is one line, or its bytes and its new line.
+func (o options) size(line []byte) int {
+	if o.limitBytes {
+		return len(line) + 1
+	}
+	return 1
+}
+
+// A limit holds the lines of a Writer limited with LimitLines or LimitBytes,
+// apart from the first ones which are stored in Buf.
+// The tail is a ring of count lines starting at first, which take size of the tail limit.
+type limit struct {
+	head    int
+	tail    [][]byte
+	first   int
+	count   int
+	size    int
+	omitted int
+	seq     int
+}
+
+// add stores the line in the buffer while it fits in the head, otherwise in the tail,
+// dropping the oldest lines until it fits.
+func (l *limit) add(buf *bytes.Buffer, o options, line []byte) {
+	size := o.size(line)
+	if l.head+size <= o.limitHead {
+		l.head += size
+		buf.Write(line)
+		buf.WriteByte('\n')
+		return
+	}
+
+	// Once a line did not fit, the head is complete, so that the lines stay in order.
+	l.head = o.limitHead
+	l.seq++
+	for l.count > 0 && l.size+size > o.limitTail {
+		l.size -= o.size(l.tail[l.first])
+		l.first = (l.first + 1) % len(l.tail)
+		l.count--
+		l.omitted++
+	}
+	if size > o.limitTail {
+		l.omitted++
+		return
+	}
+	if l.count == len(l.tail) {
+		l.grow()
+	}
+	i := (l.first + l.count) % len(l.tail)
+	l.tail[i] = append(l.tail[i][:0], line...)
+	l.count++
+	l.size += size
+}
+
+// grow makes room for more lines in the tail.
+func (l *limit) grow() {
+	tail := make([][]byte, 2*len(l.tail)+1)
+	for i := 0; i < l.count; i++ {
+		tail[i] = l.tail[(l.first+i)%len(l.tail)]
+	}
+	l.tail, l.first = tail, 0
+}
+
+// appendTo appends to raw the line replacing the omitted ones, if any, and the tail.
+func (l *limit) appendTo(raw []byte) []byte {
+	if l.omitted > 0 {
+		raw = append(raw, omitted(l.omitted)...)
+		raw = append(raw, '\n')
+	}
+	for i := 0; i < l.count; i++ {
+		raw = append(raw, l.tail[(l.first+i)%len(l.tail)]...)
+		raw = append(raw, '\n')
+	}
+	return raw
+}
+
+// drain discards the lines rendered by v, which were stored in the tail with the first head lines.
+// If lines were added to the tail after v was taken, the tail is kept as it is,
+// so that they are rendered again rather than lost.
+func (l *limit) drain(v *view, max int) {
+	l.head -= v.head
+	if l.seq == v.seq {
+		l.first, l.count, l.size, l.omitted = 0, 0, 0, 0
+	}
+	if l.count > 0 || l.omitted > 0 {
+		l.head = max
+	}
+}
+
+// omitted returns the line replacing the given number of omitted lines.
+func omitted(lines int) string {
+	if lines == 1 {
+		return "… 1 line omitted …"
+	}
+	return "… " + group(lines) + " lines omitted …"
+}
+
+// group formats i with its digits grouped by thousands.
+func group(i int) string {
+	s := strconv.Itoa(i)
+	var b []byte
+	for j, c := range []byte(s) {
+		if j > 0 && (len(s)-j)%3 == 0 {
+			b = append(b, ',')
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
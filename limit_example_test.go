@@ -0,0 +1,47 @@
+package nest
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleLimitLines() {
+	n := New()
+	noisy := WithTitledParent(n, []byte("noisy"), LimitLines(2, 2))
+	for i := 1; i <= 4316; i++ {
+		if _, err := fmt.Fprintf(noisy, "line %d", i); err != nil {
+			panic(err)
+		}
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// noisy
+	//     line 1
+	//     line 2
+	//     … 4,312 lines omitted …
+	//     line 4315
+	//     line 4316
+}
+
+func ExampleLimitBytes() {
+	n := New()
+	noisy := WithTitledParent(n, []byte("noisy"), LimitBytes(16, 16))
+	for i := 1; i <= 100; i++ {
+		if _, err := fmt.Fprintf(noisy, "line %d", i); err != nil {
+			panic(err)
+		}
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// noisy
+	//     line 1
+	//     line 2
+	//     … 97 lines omitted …
+	//     line 100
+}
@@ -0,0 +1,182 @@
+package nest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLimitLines(t *testing.T) {
+	lines := func(from, to int) string {
+		var b strings.Builder
+		for i := from; i <= to; i++ {
+			fmt.Fprintf(&b, "%d\n", i)
+		}
+		return b.String()
+	}
+
+	tests := map[string]struct {
+		head, tail int
+		written    int
+		want       string
+	}{
+		"within the limit": {
+			head: 3, tail: 2, written: 5,
+			want: lines(1, 5),
+		},
+		"head and tail": {
+			head: 2, tail: 2, written: 10,
+			want: lines(1, 2) + "… 6 lines omitted …\n" + lines(9, 10),
+		},
+		"one line omitted": {
+			head: 2, tail: 2, written: 5,
+			want: lines(1, 2) + "… 1 line omitted …\n" + lines(4, 5),
+		},
+		"head only": {
+			head: 2, written: 10,
+			want: lines(1, 2) + "… 8 lines omitted …\n",
+		},
+		"tail only": {
+			tail: 2, written: 10,
+			want: "… 8 lines omitted …\n" + lines(9, 10),
+		},
+		"thousands of lines": {
+			head: 1, tail: 1, written: 4314,
+			want: "1\n… 4,312 lines omitted …\n4314\n",
+		},
+		"negative limits": {
+			head: -1, tail: -1, written: 3,
+			want: "… 3 lines omitted …\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := New(LimitLines(test.head, test.tail))
+			for i := 1; i <= test.written; i++ {
+				if _, err := n.WriteString(fmt.Sprint(i)); err != nil {
+					t.Fatalf("could not write: %s", err)
+				}
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestLimitLines_perWriter(t *testing.T) {
+	n := New()
+	noisy := WithTitledParent(n, []byte("noisy"), LimitLines(1, 1))
+	quiet := WithTitledParent(n, []byte("quiet"))
+	_, _ = noisy.WriteString("1\n2\n3\n4")
+	_, _ = quiet.WriteString("1\n2\n3")
+	_, _ = WithParent(noisy).WriteString("a\nb\nc")
+
+	want := "noisy\n    1\n    … 2 lines omitted …\n    4\n        a\n        … 1 line omitted …\n        c\nquiet\n    1\n    2\n    3\n"
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if w.String() != want {
+		t.Error("could not match content written")
+		t.Errorf("got: %q", w.String())
+		t.Errorf("want: %q", want)
+	}
+}
+
+func TestLimitLines_drain(t *testing.T) {
+	n := New(LimitLines(1, 1))
+	_, _ = n.WriteString("1\n2\n3")
+
+	w := &bytes.Buffer{}
+	if _, err := n.Drain(w); err != nil {
+		t.Errorf("could not drain: %s", err)
+	}
+	if want := "1\n… 1 line omitted …\n3\n"; w.String() != want {
+		t.Errorf("could not match content drained: got %q, want %q", w.String(), want)
+	}
+
+	_, _ = n.WriteString("4\n5\n6")
+	w.Reset()
+	if _, err := n.WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "4\n… 1 line omitted …\n6\n"; w.String() != want {
+		t.Errorf("could not match content written after draining: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestLimitLines_allocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector is enabled")
+	}
+
+	n := New(LimitLines(10, 10))
+	line := []byte("a log line")
+	for i := 0; i < 100; i++ {
+		_, _ = n.Write(line)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, _ = n.Write(line)
+	}); allocs != 0 {
+		t.Errorf("could not write without allocating: got %v allocations", allocs)
+	}
+}
+
+func TestLimitBytes(t *testing.T) {
+	tests := map[string]struct {
+		head, tail int
+		written    []string
+		want       string
+	}{
+		"within the limit": {
+			head: 4, tail: 4, written: []string{"a", "b", "c", "d"},
+			want: "a\nb\nc\nd\n",
+		},
+		"head and tail": {
+			head: 4, tail: 4, written: []string{"a", "b", "c", "d", "e", "f", "g"},
+			want: "a\nb\n… 3 lines omitted …\nf\ng\n",
+		},
+		"long line closing the head": {
+			head: 6, tail: 6, written: []string{"a", "long line", "b", "c", "d"},
+			want: "a\n… 1 line omitted …\nb\nc\nd\n",
+		},
+		"long line in the tail": {
+			head: 2, tail: 4, written: []string{"a", "b", "long line"},
+			want: "a\n… 2 lines omitted …\n",
+		},
+		"tail of many short lines": {
+			head: 0, tail: 8, written: []string{"a", "b", "c", "d", "e", "f"},
+			want: "… 2 lines omitted …\nc\nd\ne\nf\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := New(LimitBytes(test.head, test.tail))
+			for _, line := range test.written {
+				if _, err := n.WriteString(line); err != nil {
+					t.Fatalf("could not write: %s", err)
+				}
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package nest
+
+import (
+	"bytes"
+	"io"
+)
+
+// maxHeadingLevel is the deepest heading Markdown supports.
+const maxHeadingLevel = 6
+
+// markdownSpecials are escaped wherever they appear in a line.
+var markdownSpecials = []byte("\\`*_[]<>|~&#")
+
+// A Markdown renders a Writer as a Markdown document.
+// Titles of the first HeadingDepth levels become headings, deeper titles become list items
+// holding their content and their children as nested list items.
+// Lines written to a Writer rendered as a heading become list items,
+// or paragraphs when Paragraphs is set.
+// Untitled children do not add a level, their content is rendered along the one of their parent.
+// Markdown metacharacters are escaped and leading whitespace is removed,
+// so every line is rendered as text.
+type Markdown struct {
+	Writer       *Writer
+	HeadingDepth int
+	Paragraphs   bool
+}
+
+// WriteTo writes the Markdown document to w without consuming the content of the Writer.
+// The return value n is the number of bytes written; it always fits into an
+// int, but it is int64 to match the io.WriterTo interface. Any error
+// encountered during the write is also returned.
+func (m Markdown) WriteTo(w io.Writer) (n int64, err error) {
+	if m.HeadingDepth > maxHeadingLevel {
+		m.HeadingDepth = maxHeadingLevel
+	}
+
+	r := &markdownRenderer{Markdown: m, p: m.Writer.printer(w)}
+	r.section(m.Writer.view(), 1, -1)
+	return r.p.n, r.p.err
+}
+
+// A markdownRenderer tracks the blocks written so far, to separate them with blank lines.
+type markdownRenderer struct {
+	Markdown
+	p       *printer
+	started bool
+	inList  bool
+}
+
+// section renders v, whose title is at the given heading level.
+// A negative list renders v outside of any list,
+// otherwise it is the nesting of the list v is rendered in.
+func (r *markdownRenderer) section(v *view, level, list int) {
+	if len(v.title) > 0 {
+		title := escapeMarkdown(bytes.Join(v.titleLines(), []byte{' '}))
+		switch {
+		case list < 0 && level <= r.HeadingDepth:
+			r.block(bytes.Repeat([]byte{'#'}, level), []byte{' '}, title)
+		case list < 0:
+			r.item(0, title)
+			list = 1
+		default:
+			r.item(list, title)
+			list++
+		}
+		level++
+	}
+
+	for _, line := range v.lines() {
+		if list < 0 && r.Paragraphs {
+			r.block(escapeMarkdown(line))
+			continue
+		}
+		r.item(list, escapeMarkdown(line))
+	}
+
+	for _, child := range v.children {
+		r.section(child, level, list)
+	}
+}
+
+// block writes a line as a block of its own.
+func (r *markdownRenderer) block(parts ...[]byte) {
+	if r.started {
+		r.p.line()
+	}
+	r.p.line(parts...)
+	r.started, r.inList = true, false
+}
+
+// item writes a list item nested in the given number of lists.
+func (r *markdownRenderer) item(list int, text []byte) {
+	if list < 0 {
+		list = 0
+	}
+	if r.started && !r.inList {
+		r.p.line()
+	}
+	r.p.line(bytes.Repeat([]byte("  "), list), []byte("- "), text)
+	r.started, r.inList = true, true
+}
+
+// escapeMarkdown escapes the characters of line which Markdown would interpret.
+func escapeMarkdown(line []byte) []byte {
+	line = bytes.TrimLeft(line, " \t")
+	escaped := make([]byte, 0, len(line))
+	for i, c := range line {
+		switch {
+		case bytes.IndexByte(markdownSpecials, c) >= 0,
+			i == 0 && (c == '-' || c == '+' || c == '='),
+			i > 0 && (c == '.' || c == ')') && isDigits(line[:i]):
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, c)
+	}
+	return escaped
+}
+
+// isDigits reports whether b is made of ASCII digits only.
+func isDigits(b []byte) bool {
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleMarkdown() {
+	base := New()
+
+	build := WithTitledParent(base, []byte("Build"))
+	if _, err := build.WriteString("compiled *3* packages"); err != nil {
+		panic(err)
+	}
+
+	tests := WithTitledParent(base, []byte("Tests"))
+	if _, err := tests.WriteString("1 failure"); err != nil {
+		panic(err)
+	}
+	pkg := WithTitledParent(tests, []byte("package_a"))
+	if _, err := pkg.WriteString("TestA\nTestB"); err != nil {
+		panic(err)
+	}
+
+	if _, err := (Markdown{Writer: base, HeadingDepth: 1}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// # Build
+	//
+	// - compiled \*3\* packages
+	//
+	// # Tests
+	//
+	// - 1 failure
+	// - package\_a
+	//   - TestA
+	//   - TestB
+}
+
+func ExampleMarkdown_paragraphs() {
+	base := NewSimpleWriter()
+
+	section := base.Child("Summary")
+	section.Write("All checks passed.\n1. lint")
+	section.Child("Details").Write("nothing to report")
+
+	if _, err := (Markdown{Writer: base.Writer, HeadingDepth: 2, Paragraphs: true}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// # Summary
+	//
+	// All checks passed.
+	//
+	// 1\. lint
+	//
+	// ## Details
+	//
+	// nothing to report
+}
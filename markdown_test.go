@@ -0,0 +1,89 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarkdown_WriteTo(t *testing.T) {
+	nested := New()
+	_, _ = nested.WriteString("root")
+	a := WithTitledParent(nested, []byte("a"))
+	_, _ = WithParent(a).WriteString("untitled")
+	_, _ = WithTitledParent(a, []byte("b")).WriteString("b1")
+
+	deep := New()
+	parent := deep
+	for i := 0; i < 8; i++ {
+		parent = WithTitledParent(parent, []byte("h"))
+	}
+
+	tests := map[string]struct {
+		markdown Markdown
+		want     string
+	}{
+		"lists only": {
+			markdown: Markdown{Writer: nested},
+			want:     "- root\n- a\n  - untitled\n  - b\n    - b1\n",
+		},
+		"headings and lists": {
+			markdown: Markdown{Writer: nested, HeadingDepth: 1},
+			want:     "- root\n\n# a\n\n- untitled\n- b\n  - b1\n",
+		},
+		"headings and paragraphs": {
+			markdown: Markdown{Writer: nested, HeadingDepth: 2, Paragraphs: true},
+			want:     "root\n\n# a\n\nuntitled\n\n## b\n\nb1\n",
+		},
+		"headings limited to six levels": {
+			markdown: Markdown{Writer: deep, HeadingDepth: 10},
+			want:     "# h\n\n## h\n\n### h\n\n#### h\n\n##### h\n\n###### h\n\n- h\n  - h\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			i, err := test.markdown.WriteTo(w)
+			if err != nil {
+				t.Errorf("could not write markdown to the writer: %s", err)
+			}
+			if i != int64(len(test.want)) {
+				t.Error("could not match written bytes")
+				t.Errorf("got: %d", i)
+				t.Errorf("want: %d", len(test.want))
+			}
+			if w.String() != test.want {
+				t.Error("could not match markdown written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		line string
+		want string
+	}{
+		"plain text":         {line: "plain text", want: "plain text"},
+		"emphasis and code":  {line: "*a* _b_ `c`", want: "\\*a\\* \\_b\\_ \\`c\\`"},
+		"links and html":     {line: "[a](b) <i>&amp;", want: "\\[a\\](b) \\<i\\>\\&amp;"},
+		"heading":            {line: "# title", want: "\\# title"},
+		"bullet list":        {line: "- item", want: "\\- item"},
+		"ordered list":       {line: "12. item 3.", want: "12\\. item 3."},
+		"ordered list paren": {line: "1) item", want: "1\\) item"},
+		"leading whitespace": {line: "    code", want: "code"},
+		"table":              {line: "a | b", want: "a \\| b"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := string(escapeMarkdown([]byte(test.line))); got != test.want {
+				t.Error("could not match escaped line")
+				t.Errorf("got: %s", got)
+				t.Errorf("want: %s", test.want)
+			}
+		})
+	}
+}
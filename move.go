@@ -0,0 +1,179 @@
+package nest
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrCycle is returned when moving a Writer under itself or under one of its Children.
+	ErrCycle = errors.New("nest: Writer cannot be moved under itself")
+	// ErrNoParent is returned when reordering a Writer which has no parent.
+	ErrNoParent = errors.New("nest: Writer has no parent")
+	// ErrStreaming is returned when moving a streaming Writer, whose content may be already written.
+	ErrStreaming = errors.New("nest: streaming Writer cannot be moved")
+)
+
+// moving serializes the changes to the shape of the trees,
+// so that concurrent moves cannot create a cycle.
+var moving sync.Mutex
+
+// Remove detaches child from the Children of n, the child becomes the root of its own tree
+// and it is rendered as such.
+// A titled child keeps the Depth 1 of a titled child of a root,
+// so that its content is still rendered one level below its Title.
+// It reports whether child was one of the Children of n;
+// the Children of a streaming Writer are never removed.
+// Once a detached child is closed, it is no longer waited for by the Done channel of n.
+func (n *Writer) Remove(child *Writer) bool {
+	if n.stream != nil {
+		return false
+	}
+	moving.Lock()
+	defer moving.Unlock()
+
+	if !n.detach(child) {
+		return false
+	}
+	child.attach(nil)
+	return true
+}
+
+// MoveTo moves n, and all its Children, from its parent to the end of the Children of newParent,
+// fixing up their Depth.
+// A Writer cannot be moved under itself or under one of its Children, in which case ErrCycle is returned.
+// Moving a streaming Writer fails with ErrStreaming, and moving it under a closed Writer with ErrClosed.
+func (n *Writer) MoveTo(newParent *Writer) error {
+	if n.stream != nil || newParent.stream != nil {
+		return ErrStreaming
+	}
+	moving.Lock()
+	defer moving.Unlock()
+
+	for ancestor := newParent; ancestor != nil; ancestor = ancestor.parentWriter() {
+		if ancestor == n {
+			return ErrCycle
+		}
+	}
+	newParent.mutex.Lock()
+	closed := newParent.closed
+	newParent.mutex.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	if parent := n.parentWriter(); parent != nil {
+		parent.detach(n)
+	}
+	n.attach(newParent)
+	return nil
+}
+
+// InsertAt moves n to position i among the Children of its parent,
+// an index out of range moves it to the closest end.
+// It fails with ErrNoParent if n has no parent,
+// and with ErrStreaming if n is a streaming Writer, whose siblings are written in the order they were created.
+func (n *Writer) InsertAt(i int) error {
+	if n.stream != nil {
+		return ErrStreaming
+	}
+	moving.Lock()
+	defer moving.Unlock()
+
+	parent := n.parentWriter()
+	if parent == nil {
+		return ErrNoParent
+	}
+	parent.mutex.Lock()
+	defer parent.mutex.Unlock()
+
+	children := parent.Children[:0]
+	for _, child := range parent.Children {
+		if child != n {
+			children = append(children, child)
+		}
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i > len(children) {
+		i = len(children)
+	}
+	children = append(children, nil)
+	copy(children[i+1:], children[i:])
+	children[i] = n
+	parent.Children = children
+	return nil
+}
+
+// detach removes child from the Children of n, and stops waiting for it;
+// it reports whether child was found.
+func (n *Writer) detach(child *Writer) bool {
+	n.mutex.Lock()
+	found := false
+	for i, c := range n.Children {
+		if c == child {
+			n.Children = append(n.Children[:i:i], n.Children[i+1:]...)
+			found = true
+			break
+		}
+	}
+	n.mutex.Unlock()
+	if !found {
+		return false
+	}
+
+	child.mutex.Lock()
+	finished := child.finished
+	child.parent = nil
+	child.mutex.Unlock()
+	if !finished {
+		n.mutex.Lock()
+		n.open--
+		n.mutex.Unlock()
+		n.finish()
+	}
+	return true
+}
+
+// attach appends n to the Children of parent, which waits for it until it is finished,
+// and moves the tree of n to the depth below parent.
+// A nil parent makes n the root of its tree, at depth 1 if n is titled.
+func (n *Writer) attach(parent *Writer) {
+	depth := 0
+	n.mutex.Lock()
+	n.parent = parent
+	if parent == nil && len(n.Title) > 0 {
+		depth = 1
+	}
+	if parent != nil {
+		parent.mutex.Lock()
+		depth = parent.Depth + 1
+		parent.Children = append(parent.Children, n)
+		if !n.finished {
+			parent.open++
+		}
+		parent.mutex.Unlock()
+	}
+	n.mutex.Unlock()
+	n.setDepth(depth)
+}
+
+// setDepth sets the Depth of n and of its Children from the given depth.
+func (n *Writer) setDepth(depth int) {
+	n.mutex.Lock()
+	n.Depth = depth
+	children := append([]*Writer(nil), n.Children...)
+	n.mutex.Unlock()
+
+	for _, child := range children {
+		child.setDepth(depth + 1)
+	}
+}
+
+// parentWriter returns the parent of n, which is changed by the moves.
+func (n *Writer) parentWriter() *Writer {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.parent
+}
@@ -0,0 +1,58 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleWriter_InsertAt() {
+	base := New()
+	for _, name := range []string{"TestA", "TestB", "TestC"} {
+		test := WithTitledParent(base, []byte(name))
+		if name == "TestB" {
+			if _, err := test.WriteString("FAIL"); err != nil {
+				panic(err)
+			}
+			if err := test.InsertAt(0); err != nil {
+				panic(err)
+			}
+			continue
+		}
+		if _, err := test.WriteString("ok"); err != nil {
+			panic(err)
+		}
+	}
+
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// TestB
+	//     FAIL
+	// TestA
+	//     ok
+	// TestC
+	//     ok
+}
+
+func ExampleWriter_MoveTo() {
+	base := New()
+	passed := WithTitledParent(base, []byte("Passed"))
+	failed := WithTitledParent(base, []byte("Failed"))
+
+	test := WithTitledParent(passed, []byte("TestA"))
+	if _, err := test.WriteString("expected 1, got 2"); err != nil {
+		panic(err)
+	}
+	if err := test.MoveTo(failed); err != nil {
+		panic(err)
+	}
+
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Passed
+	// Failed
+	//     TestA
+	//         expected 1, got 2
+}
@@ -0,0 +1,273 @@
+package nest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestWriter_Remove(t *testing.T) {
+	n := New()
+	a := WithTitledParent(n, []byte("a"))
+	b := WithTitledParent(a, []byte("b"))
+	_, _ = b.WriteString("content")
+
+	if n.Remove(b) {
+		t.Error("could not refuse to remove a Writer which is not a child")
+	}
+	if !a.Remove(b) {
+		t.Fatal("could not remove a child")
+	}
+	if len(a.Children) != 0 {
+		t.Errorf("could not detach the child: %d children left", len(a.Children))
+	}
+	if b.Depth != 1 {
+		t.Errorf("could not match depth: got %d, want 1", b.Depth)
+	}
+	if got, want := b.Buf.String(), "content\n"; got != want {
+		t.Errorf("could not match content: got %q, want %q", got, want)
+	}
+
+	untitled := WithParent(a)
+	if !a.Remove(untitled) {
+		t.Fatal("could not remove an untitled child")
+	}
+	if untitled.Depth != 0 {
+		t.Errorf("could not match depth: got %d, want 0", untitled.Depth)
+	}
+
+	s := NewStream(&bytes.Buffer{})
+	c := WithParent(s)
+	if s.Remove(c) {
+		t.Error("could not refuse to remove the child of a streaming Writer")
+	}
+}
+
+func TestWriter_Remove_titled(t *testing.T) {
+	n := New()
+	a := WithTitledParent(n, []byte("a"))
+	_, _ = a.WriteString("a content")
+	b := WithTitledParent(a, []byte("b"))
+	_, _ = b.WriteString("b content")
+	n.Remove(a)
+
+	var got bytes.Buffer
+	if _, err := a.WriteTo(&got); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	want := "a\n    a content\n    b\n        b content\n"
+	if got.String() != want {
+		t.Error("could not match the detached titled child")
+		t.Errorf("got: %q", got.String())
+		t.Errorf("want: %q", want)
+	}
+}
+
+func TestWriter_MoveTo(t *testing.T) {
+	closed := New()
+	_ = closed.Close()
+	stream := NewStream(&bytes.Buffer{})
+
+	tests := map[string]struct {
+		build func() (n, parent, root *Writer)
+		want  string
+		error error
+	}{
+		"move deeper": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				b := WithTitledParent(root, []byte("b"))
+				_, _ = b.WriteString("b content")
+				c := WithTitledParent(b, []byte("c"))
+				_, _ = c.WriteString("c content")
+				return b, a, root
+			},
+			want: "a\n    b\n        b content\n        c\n            c content\n",
+		},
+		"move shallower": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				b := WithTitledParent(a, []byte("b"))
+				_, _ = b.WriteString("b content")
+				return b, root, root
+			},
+			want: "a\nb\n    b content\n",
+		},
+		"move a root": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				_, _ = root.WriteString("root content")
+				other := New()
+				_, _ = other.WriteString("other content")
+				return other, root, root
+			},
+			want: "root content\n    other content\n",
+		},
+		"move under itself": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				return a, a, root
+			},
+			want:  "a\n",
+			error: ErrCycle,
+		},
+		"move under a child": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				b := WithTitledParent(a, []byte("b"))
+				return a, b, root
+			},
+			want:  "a\n    b\n",
+			error: ErrCycle,
+		},
+		"move under a closed Writer": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				return a, closed, root
+			},
+			want:  "a\n",
+			error: ErrClosed,
+		},
+		"move under a streaming Writer": {
+			build: func() (*Writer, *Writer, *Writer) {
+				root := New()
+				a := WithTitledParent(root, []byte("a"))
+				return a, stream, root
+			},
+			want:  "a\n",
+			error: ErrStreaming,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, parent, root := test.build()
+			if err := n.MoveTo(parent); err != test.error {
+				t.Errorf("could not match error: got %v, want %v", err, test.error)
+			}
+			buf := &bytes.Buffer{}
+			if _, err := root.WriteTo(buf); err != nil {
+				t.Fatalf("could not write: %s", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Error("could not match tree")
+				t.Errorf("got: %q", got)
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWriter_MoveTo_done(t *testing.T) {
+	a := New()
+	b := New()
+	child := WithParent(a)
+	_ = a.Close()
+	_ = b.Close()
+
+	if err := child.MoveTo(b); err != ErrClosed {
+		t.Errorf("could not match error: got %v, want %v", err, ErrClosed)
+	}
+	if !a.Remove(child) {
+		t.Fatal("could not remove the child")
+	}
+	select {
+	case <-a.Done():
+	default:
+		t.Error("could not stop waiting for a removed child")
+	}
+
+	c := New()
+	if err := child.MoveTo(c); err != nil {
+		t.Fatalf("could not move: %s", err)
+	}
+	_ = c.Close()
+	select {
+	case <-c.Done():
+		t.Error("could not wait for a moved child")
+	default:
+	}
+	_ = child.Close()
+	select {
+	case <-c.Done():
+	default:
+		t.Error("could not finish once the moved child is closed")
+	}
+}
+
+func TestWriter_InsertAt(t *testing.T) {
+	tests := map[string]struct {
+		index int
+		want  string
+	}{
+		"first":            {index: 0, want: "c\na\nb\n"},
+		"middle":           {index: 1, want: "a\nc\nb\n"},
+		"last":             {index: 2, want: "a\nb\nc\n"},
+		"before the first": {index: -1, want: "c\na\nb\n"},
+		"after the last":   {index: 10, want: "a\nb\nc\n"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := New()
+			_ = WithTitledParent(root, []byte("a"))
+			_ = WithTitledParent(root, []byte("b"))
+			c := WithTitledParent(root, []byte("c"))
+
+			if err := c.InsertAt(test.index); err != nil {
+				t.Fatalf("could not insert: %s", err)
+			}
+			buf := &bytes.Buffer{}
+			if _, err := root.WriteTo(buf); err != nil {
+				t.Fatalf("could not write: %s", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("could not match tree: got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	if err := New().InsertAt(0); err != ErrNoParent {
+		t.Errorf("could not match error: got %v, want %v", err, ErrNoParent)
+	}
+	stream := NewStream(&bytes.Buffer{})
+	_ = WithTitledParent(stream, []byte("x"))
+	if err := WithTitledParent(stream, []byte("y")).InsertAt(0); err != ErrStreaming {
+		t.Errorf("could not match error: got %v, want %v", err, ErrStreaming)
+	}
+}
+
+func TestWriter_MoveTo_raceConditions(t *testing.T) {
+	root := New()
+	a := WithTitledParent(root, []byte("a"))
+	b := WithTitledParent(root, []byte("b"))
+	child := WithTitledParent(a, []byte("child"))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			_ = child.MoveTo(b)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = b.MoveTo(child)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = child.WriteString("content")
+			_ = child.InsertAt(0)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = root.WriteTo(&bytes.Buffer{})
+		}()
+	}
+	wg.Wait()
+}
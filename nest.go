@@ -2,109 +2,430 @@ package nest
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"strings"
 	"sync"
 )
 
+// ErrClosed is returned when writing to, or closing, a closed Writer.
+var ErrClosed = errors.New("nest: Writer is closed")
+
 // A Writer represents an active nestable writer.
-// Each write operation makes a single call to
-// the bytes.Buffer's Write method.
+// Each write operation appends the content to the bytes.Buffer,
+// followed by a new line, without allocating once the buffer has grown.
+// Buf holds the lines written to the Writer without indentation,
+// which is added depending on the Depth when the Writer is rendered.
 // A Writer can be used simultaneously from multiple goroutines;
 // it guarantees to serialize access to the buffer.
 type Writer struct {
 	Buf      *bytes.Buffer
-	Depth    uint8
+	Depth    int
 	Children []*Writer
+	Title    []byte
+
+	opts     options
+	key      int
+	style    Style
+	parent   *Writer
+	mutex    sync.Mutex
+	closed   bool
+	open     int
+	finished bool
+	done     chan struct{}
+	partial  []byte
+	limit    *limit
 
-	mutex sync.Mutex
+	// pending, live, ended, outline and titled are guarded by the mutex of the stream.
+	stream  *stream
+	pending []segment
+	live    bool
+	ended   bool
+	outline []int
+	titled  int
 }
 
 // New creates a new Writer with a inner buffer.
-func New() *Writer {
-	return &Writer{
+func New(opts ...Option) *Writer {
+	n := &Writer{
 		Buf: &bytes.Buffer{},
 	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
 }
 
 // WithParent creates a new Writer from a parent one.
-func WithParent(parent *Writer) *Writer {
-	return WithTitledParent(parent, nil)
+func WithParent(parent *Writer, opts ...Option) *Writer {
+	return WithTitledParent(parent, nil, opts...)
 }
 
 // WithTitledParent creates a new Writer with a title.
-// The title appears as a fist non-indented line on top of the content.
-func WithTitledParent(parent *Writer, t []byte) *Writer {
+// The title is kept apart from the content, and is rendered
+// as a fist non-indented line on top of it.
+// The new Writer inherits the options of its parent.
+// A Writer created from a closed parent is closed as well.
+func WithTitledParent(parent *Writer, t []byte, opts ...Option) *Writer {
 	child := &Writer{
-		Buf:   bytes.NewBuffer(format(t, int(parent.Depth))),
-		Depth: parent.Depth + 1,
+		Buf:    &bytes.Buffer{},
+		Title:  append([]byte(nil), t...),
+		opts:   parent.opts,
+		parent: parent,
+		stream: parent.stream,
+	}
+	for _, opt := range opts {
+		opt(child)
 	}
+	if child.opts.stripANSI {
+		child.Title = stripEscapes(child.Title)
+	}
+	child.Title = child.opts.normalize(child.Title)
 	parent.mutex.Lock()
+	child.Depth = parent.Depth + 1
 	parent.Children = append(parent.Children, child)
+	if parent.closed {
+		child.closed, child.finished, child.ended = true, true, true
+	} else {
+		parent.open++
+	}
 	parent.mutex.Unlock()
+	if child.stream != nil {
+		child.stream.add(parent, child)
+	}
 	return child
 }
 
 // Write wraps a call to the inner bytes.Buffer's Write method.
-// The content p is formatted and indented depending on the Depth of the Writer.
+// The content p is stored as one or more lines, each terminated by a new line,
+// and it is indented depending on the Depth of the Writer when it is rendered.
+// Writing to a closed Writer fails with ErrClosed.
+// When the Writer is LineBuffered, only complete lines are stored.
+// The return value is len(p).
 func (n *Writer) Write(p []byte) (int, error) {
-	p = format(p, int(n.Depth))
+	if n.opts.lineBuffered {
+		return n.writeBuffered(p)
+	}
+	if n.stream != nil {
+		return n.writeStream(p)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if err := n.writable(); err != nil || len(p) == 0 {
+		return 0, err
+	}
+	if err := n.store(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writable returns the error a write to n fails with, if any; the mutex must be held.
+func (n *Writer) writable() error {
+	if n.opts.beyond(n.Depth, DepthError) {
+		return ErrMaxDepth
+	}
+	if n.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// writeStream writes p indented through the stream.
+// The Depth of a streaming Writer never changes, so the mutex is not needed.
+func (n *Writer) writeStream(p []byte) (int, error) {
+	if n.opts.beyond(n.Depth, DepthError) {
+		return 0, ErrMaxDepth
+	}
+	content := p
+	if n.opts.stripANSI {
+		content = stripEscapes(content)
+	}
+	content = n.opts.normalize(content)
+	if _, err := n.stream.write(n, n.streamed(content), n.opts.prefix(n.Depth), appendFormat); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeBuffered stores the complete lines of p along the data held by a previous write,
+// and holds the data following the last new line.
+func (n *Writer) writeBuffered(p []byte) (int, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if err := n.writable(); err != nil {
+		return 0, err
+	}
+
+	n.partial = append(n.partial, p...)
+	i := bytes.LastIndexByte(n.partial, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	err := n.store(n.partial[:i])
+	n.partial = append(n.partial[:0], n.partial[i+1:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush stores the data held by a LineBuffered Writer as a line of its own,
+// even if no new line was written after it.
+func (n *Writer) Flush() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.flush()
+}
+
+// flush stores the held data; the mutex must be held.
+func (n *Writer) flush() error {
+	if len(n.partial) == 0 {
+		return nil
+	}
+	err := n.store(n.partial)
+	n.partial = n.partial[:0]
+	return err
+}
+
+// store writes the lines of p to the buffer, or indented to the stream,
+// even when p is empty; the mutex must be held.
+func (n *Writer) store(p []byte) error {
+	if n.opts.stripANSI {
+		p = stripEscapes(p)
+	}
+	p = n.opts.normalize(p)
+	if n.stream != nil {
+		_, err := n.stream.write(n, n.streamed(p), n.opts.prefix(n.Depth), appendLines)
+		return err
+	}
+	if n.opts.limited {
+		n.storeLimited(p)
+		return nil
+	}
+	n.Buf.Write(p)
+	n.Buf.WriteByte('\n')
+	return nil
+}
+
+// streamed returns p prepared for a terminal and styled, as it is written through the stream.
+func (n *Writer) streamed(p []byte) []byte {
+	p = n.opts.terminal(p, n.opts.prefix(n.Depth))
+	if n.stream.color {
+		content, _ := n.styles(n.Depth)
+		p = content.apply(p)
+	}
+	return p
+}
+
+// storeLimited stores the lines of p as LimitLines or LimitBytes allows; the mutex must be held.
+func (n *Writer) storeLimited(p []byte) {
+	if n.limit == nil {
+		n.limit = &limit{}
+	}
+	for {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			n.limit.add(n.Buf, n.opts, p)
+			return
+		}
+		n.limit.add(n.Buf, n.opts, p[:i])
+		p = p[i+1:]
+	}
+}
+
+// writeLine writes a single line, even when it is empty.
+func (n *Writer) writeLine(line []byte) {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
-	return n.Buf.Write(p)
+	_ = n.store(line)
 }
 
-// WriteString wraps a call to a Writer.Write.
+// content returns a copy of the lines held by n; the mutex must be held.
+func (n *Writer) content() []byte {
+	raw := append([]byte(nil), n.Buf.Bytes()...)
+	if n.limit != nil {
+		raw = n.limit.appendTo(raw)
+	}
+	return raw
+}
+
+// written reports whether n holds any content; the mutex must be held.
+func (n *Writer) written() bool {
+	return n.Buf.Len() > 0 || n.limit != nil && (n.limit.count > 0 || n.limit.omitted > 0)
+}
+
+// WriteString works like Write, without copying s to a byte slice
+// unless its content has to be transformed before being stored.
 func (n *Writer) WriteString(s string) (int, error) {
-	return n.Write([]byte(s))
+	if !n.direct() || strings.IndexByte(s, '\r') >= 0 {
+		return n.Write([]byte(s))
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if err := n.writable(); err != nil || len(s) == 0 {
+		return 0, err
+	}
+	n.Buf.WriteString(s)
+	n.Buf.WriteByte('\n')
+	return len(s), nil
+}
+
+// direct reports whether the content written to n is stored as it is, unless it has carriage returns.
+func (n *Writer) direct() bool {
+	return !n.opts.lineBuffered && n.stream == nil && !n.opts.limited && !n.opts.stripANSI
+}
+
+// Close marks the Writer as finished: further writes fail with ErrClosed,
+// and so does closing it again.
+// The data held by a LineBuffered Writer is flushed first.
+// Once the Writer and all its Children are closed, the channel returned by Done is closed.
+// On a streaming Writer it lets the content queued behind it be written,
+// and it returns the first error encountered while writing to the underlying io.Writer.
+func (n *Writer) Close() error {
+	n.mutex.Lock()
+	if n.closed {
+		n.mutex.Unlock()
+		return ErrClosed
+	}
+	flushErr := n.flush()
+	n.closed = true
+	n.mutex.Unlock()
+
+	n.finish()
+	if n.stream != nil {
+		if err := n.stream.close(n); err != nil {
+			return err
+		}
+	}
+	return flushErr
+}
+
+// Done returns a channel that is closed once the Writer and all its Children are closed,
+// so that a parent, or a renderer, can wait for a section to be complete.
+func (n *Writer) Done() <-chan struct{} {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.done == nil {
+		n.done = make(chan struct{})
+		if n.finished {
+			close(n.done)
+		}
+	}
+	return n.done
+}
+
+// finish closes the Done channel of n once n and its Children are closed,
+// and then does the same with its parent, which might be waiting for n only.
+func (n *Writer) finish() {
+	for n != nil {
+		n.mutex.Lock()
+		if !n.closed || n.open > 0 || n.finished {
+			n.mutex.Unlock()
+			return
+		}
+		n.finished = true
+		if n.done != nil {
+			close(n.done)
+		}
+		parent := n.parent
+		n.mutex.Unlock()
+
+		n = parent
+		if n != nil {
+			n.mutex.Lock()
+			n.open--
+			n.mutex.Unlock()
+		}
+	}
 }
 
-// WriteTo wraps a call to the inner bytes.Buffer's WriteTo method.
+// WriteTo writes the content of the inner bytes.Buffer to w, without consuming it,
+// so the same Writer can be written more than once.
+// The Title, if any, is written on top of the data of the Writer.
 // Once the data on the Writer is fully written,
 // then the data of each Children is gonna be written
 // The return value i is the number of bytes written; it always fits into an
 // int, but it is int64 to match the io.WriterTo interface. Any error
 // encountered during the write is also returned.
 func (n *Writer) WriteTo(w io.Writer) (i int64, err error) {
+	return n.writeTo(w, false)
+}
+
+// Drain works like WriteTo, but it consumes the data written to the Writer and its Children
+// as the inner bytes.Buffer's WriteTo method does.
+// Titles and Children are kept, so the Writer can be written again
+// and will contain only the data written after the call.
+func (n *Writer) Drain(w io.Writer) (i int64, err error) {
+	return n.writeTo(w, true)
+}
+
+// Reset discards the data written to the Writer and its Children,
+// including the data held by a LineBuffered Writer.
+// Titles and Children are kept.
+func (n *Writer) Reset() {
 	n.mutex.Lock()
-	func() {
-		defer n.mutex.Unlock()
-		ii, writeErr := n.Buf.WriteTo(w)
-		i = i + ii
-		if writeErr != nil {
-			err = writeErr
-			return
-		}
-	}()
+	n.Buf.Reset()
+	n.limit = nil
+	n.partial = n.partial[:0]
+	n.mutex.Unlock()
 
-	for _, child := range n.Children {
-		ii, writeErr := child.WriteTo(w)
-		i = i + ii
-		if writeErr != nil {
-			err = writeErr
-			return
-		}
+	for _, child := range n.children() {
+		child.Reset()
 	}
+}
 
-	return
+func (n *Writer) writeTo(w io.Writer, drain bool) (int64, error) {
+	p := n.printer(w)
+	n.view().writeTo(p, drain)
+	return p.n, p.err
 }
 
-func format(p []byte, depth int) []byte {
+// Lines returns a copy of the content written to the Writer, split in lines.
+// Unlike reading Buf, it is safe to call while other goroutines write to the Writer.
+func (n *Writer) Lines() [][]byte {
+	n.mutex.Lock()
+	raw := n.content()
+	n.mutex.Unlock()
+	return split(raw)
+}
+
+// children returns a snapshot of the Children of the Writer.
+func (n *Writer) children() []*Writer {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]*Writer(nil), n.Children...)
+}
+
+// ordered returns a snapshot of the Children of the Writer, in the order they are rendered.
+func (n *Writer) ordered() []*Writer {
+	children := n.children()
+	n.opts.sort(children)
+	return children
+}
+
+// appendFormat works like appendLines, but it appends nothing when p is empty.
+func appendFormat(dst, p, prefix []byte) []byte {
 	if len(p) == 0 {
-		return p
+		return dst
 	}
+	return appendLines(dst, p, prefix)
+}
 
-	//TODO: optimize p2 slice allocation
-	var p2, prefix []byte
-	prefix = bytes.Repeat([]byte{' ', ' ', ' ', ' '}, depth)
-
-	for i, line := range bytes.Split(p, []byte{'\n'}) {
-		if i > 0 {
-			p2 = append(p2, '\n')
+// appendLines appends each line of p indented to dst, p being formatted even when it is empty.
+// The content following a carriage return is indented as well.
+func appendLines(dst, p, prefix []byte) []byte {
+	for {
+		dst = append(dst, prefix...)
+		i := bytes.IndexAny(p, "\r\n")
+		if i < 0 {
+			dst = append(dst, p...)
+			break
 		}
-		p2 = append(p2, prefix...)
-		p2 = append(p2, line...)
+		dst = append(dst, p[:i+1]...)
+		p = p[i+1:]
 	}
 
-	p2 = append(p2, '\n')
-	return p2
+	dst = append(dst, '\n')
+	return dst
 }
@@ -1,6 +1,8 @@
 package nest
 
 import (
+	"fmt"
+	"log"
 	"os"
 	"sync"
 )
@@ -168,6 +170,24 @@ func ExampleWithTitledParent() {
 	//     indented
 }
 
+func ExampleIndent() {
+	n := New(Indent("  "))
+	section := WithTitledParent(n, []byte("Section 1"))
+	sub := WithTitledParent(section, []byte("Section 1.1"))
+
+	if _, err := sub.WriteString("indented by two spaces per level"); err != nil {
+		panic(err)
+	}
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// Section 1
+	//   Section 1.1
+	//     indented by two spaces per level
+}
+
 func ExampleWriter_Write() {
 	n := New()
 	if _, err := n.Write([]byte("line one")); err != nil {
@@ -229,3 +249,235 @@ func ExampleWriter_WriteTo() {
 
 	// Output:
 }
+
+func ExampleWriter_WriteTo_twice() {
+	n := New()
+	if _, err := n.WriteString("line one"); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// line one
+	// line one
+}
+
+func ExampleWriter_Drain() {
+	n := New()
+	section := WithTitledParent(n, []byte("Section 1"))
+	if _, err := section.WriteString("first"); err != nil {
+		panic(err)
+	}
+	if _, err := n.Drain(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	if _, err := section.WriteString("second"); err != nil {
+		panic(err)
+	}
+	if _, err := n.Drain(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// Section 1
+	//     first
+	// Section 1
+	//     second
+}
+
+func ExampleWriter_Reset() {
+	n := New()
+	if _, err := n.WriteString("discarded"); err != nil {
+		panic(err)
+	}
+	n.Reset()
+
+	if _, err := n.WriteString("line one"); err != nil {
+		panic(err)
+	}
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// line one
+}
+
+func ExampleWriter_Close() {
+	n := New()
+	if err := n.Close(); err != nil {
+		panic(err)
+	}
+
+	_, err := n.WriteString("too late")
+	fmt.Println(err)
+
+	// Output:
+	// nest: Writer is closed
+}
+
+func ExampleWriter_Done() {
+	base := New()
+	wg := sync.WaitGroup{}
+	for _, title := range []string{"First job", "Second job"} {
+		job := WithTitledParent(base, []byte(title))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := job.WriteString("done"); err != nil {
+				panic(err)
+			}
+			if err := job.Close(); err != nil {
+				panic(err)
+			}
+		}()
+	}
+	if err := base.Close(); err != nil {
+		panic(err)
+	}
+
+	<-base.Done()
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	wg.Wait()
+
+	// Output:
+	// First job
+	//     done
+	// Second job
+	//     done
+}
+
+func ExampleLineBuffered() {
+	n := New(LineBuffered())
+	section := WithTitledParent(n, []byte("Logs"))
+
+	logger := log.New(section, "", 0)
+	logger.Println("first entry")
+	logger.Print("second entry\nspanning two lines")
+
+	fmt.Fprint(section, "written ", "in ", "chunks")
+	if err := section.Close(); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Logs
+	//     first entry
+	//     second entry
+	//     spanning two lines
+	//     written in chunks
+}
+
+func ExampleMaxDepth() {
+	n := New(MaxDepth(1, DepthCollapse))
+	parent := n
+	for _, title := range []string{"Level 1", "Level 2", "Level 3"} {
+		parent = WithTitledParent(parent, []byte(title))
+		if _, err := parent.WriteString("content"); err != nil {
+			panic(err)
+		}
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Level 1
+	//     content
+	//     … (2 more levels)
+}
+
+func ExampleOmitEmpty() {
+	n := New(OmitEmpty())
+	for _, component := range []string{"api", "database", "cache"} {
+		section := WithTitledParent(n, []byte(component))
+		if component == "database" {
+			if _, err := section.WriteString("connection refused"); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// database
+	//     connection refused
+}
+
+func ExampleMarkEmpty() {
+	n := New(MarkEmpty("(empty)"))
+	for _, component := range []string{"api", "database", "cache"} {
+		section := WithTitledParent(n, []byte(component))
+		if component == "database" {
+			if _, err := section.WriteString("connection refused"); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// api (empty)
+	// database
+	//     connection refused
+	// cache (empty)
+}
+
+func ExampleKey() {
+	wg := sync.WaitGroup{}
+	base := New(SortBy(ByKey))
+
+	for i, item := range []string{"1. Item one", "2. Item two", "3. Item three"} {
+		wg.Add(1)
+		go func(i int, item string) {
+			defer wg.Done()
+			child := WithTitledParent(base, []byte(item), Key(i))
+			if _, err := child.WriteString("Written item"); err != nil {
+				panic(err)
+			}
+		}(i, item)
+	}
+
+	wg.Wait()
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 1. Item one
+	//     Written item
+	// 2. Item two
+	//     Written item
+	// 3. Item three
+	//     Written item
+}
+
+func ExampleSortBy() {
+	base := NewSimpleWriter(SortBy(ByTitle))
+	base.Child("beta").Write("second")
+	base.Child("alpha").Write("first")
+
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// alpha
+	//     first
+	// beta
+	//     second
+}
@@ -3,13 +3,14 @@ package nest
 import (
 	"bytes"
 	"io/ioutil"
+	"strings"
 	"sync"
 	"testing"
 )
 
 func TestNew(t *testing.T) {
 	type want struct {
-		depth    uint8
+		depth    int
 		children int
 	}
 
@@ -99,12 +100,22 @@ func TestWriter_Write(t *testing.T) {
 			p:    []byte("a long string!\nwith a new line!"),
 			want: []byte("        a long string!\n        with a new line!\n"),
 		},
+		"string with new lines with a two depth Writer indented by tabs": {
+			nest: WithParent(WithParent(New(Indent("\t")))),
+			p:    []byte("a long string!\nwith a new line!"),
+			want: []byte("\t\ta long string!\n\t\twith a new line!\n"),
+		},
+		"one line string with a one depth Writer overriding the indentation": {
+			nest: WithParent(New(), Indent("  ")),
+			p:    []byte("a long string!"),
+			want: []byte("  a long string!\n"),
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			i, err := test.nest.Write(test.p)
-			if i != len(test.want) {
+			if i != len(test.p) {
 				t.Error("could not match bytes written")
 				t.Errorf("got: %d", i)
 				t.Errorf("want: %d", len(test.p))
@@ -112,9 +123,13 @@ func TestWriter_Write(t *testing.T) {
 			if err != nil {
 				t.Errorf("could not write string to Writer: %s", err)
 			}
-			if !bytes.Equal(test.want, test.nest.Buf.Bytes()) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write content to the writer: %s", err)
+			}
+			if !bytes.Equal(test.want, w.Bytes()) {
 				t.Error("could not match string written")
-				t.Errorf("got: %s", test.nest.Buf.String())
+				t.Errorf("got: %s", w.String())
 				t.Errorf("want: %s", test.want)
 			}
 		})
@@ -152,7 +167,7 @@ func TestWriter_WriteString(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			i, err := test.nest.WriteString(test.s)
-			if i != len(test.want) {
+			if i != len(test.s) {
 				t.Error("could not match bytes written")
 				t.Errorf("got: %d", i)
 				t.Errorf("want: %d", len(test.s))
@@ -160,9 +175,13 @@ func TestWriter_WriteString(t *testing.T) {
 			if err != nil {
 				t.Errorf("could not write string to Writer: %s", err)
 			}
-			if test.want != test.nest.Buf.String() {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write content to the writer: %s", err)
+			}
+			if test.want != w.String() {
 				t.Error("could not match string written")
-				t.Errorf("got: %s", test.nest.Buf.String())
+				t.Errorf("got: %s", w.String())
 				t.Errorf("want: %s", test.want)
 			}
 		})
@@ -187,11 +206,11 @@ func TestWriter_WriteTo(t *testing.T) {
 				Children: []*Writer{
 					{
 						Depth: 1,
-						Buf:   bytes.NewBuffer([]byte("    two\n")),
+						Buf:   bytes.NewBuffer([]byte("two\n")),
 						Children: []*Writer{
 							{
 								Depth: 2,
-								Buf:   bytes.NewBuffer([]byte("        three\n")),
+								Buf:   bytes.NewBuffer([]byte("three\n")),
 							},
 						},
 					},
@@ -212,10 +231,80 @@ func TestWriter_WriteTo(t *testing.T) {
 				t.Errorf("got: %d", i)
 				t.Errorf("want: %d", test.i)
 			}
+
+			w2 := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w2); err != nil {
+				t.Errorf("could not write simple content to the writer twice: %s", err)
+			}
+			if w.String() != w2.String() {
+				t.Error("could not match content written twice")
+				t.Errorf("got: %s", w2.String())
+				t.Errorf("want: %s", w.String())
+			}
 		})
 	}
 }
 
+func TestWriter_Drain(t *testing.T) {
+	n := New()
+	_, _ = n.WriteString("one")
+	child := WithTitledParent(n, []byte("title"))
+	_, _ = child.WriteString("two")
+
+	tests := []struct {
+		write string
+		want  string
+	}{
+		{
+			want: "one\ntitle\n    two\n",
+		},
+		{
+			want: "title\n",
+		},
+		{
+			write: "three",
+			want:  "title\n    three\n",
+		},
+	}
+
+	for _, test := range tests {
+		if test.write != "" {
+			_, _ = child.WriteString(test.write)
+		}
+		w := &bytes.Buffer{}
+		i, err := n.Drain(w)
+		if err != nil {
+			t.Errorf("could not drain the writer: %s", err)
+		}
+		if i != int64(len(test.want)) {
+			t.Error("could not match drained bytes")
+			t.Errorf("got: %d", i)
+			t.Errorf("want: %d", len(test.want))
+		}
+		if w.String() != test.want {
+			t.Error("could not match drained content")
+			t.Errorf("got: %s", w.String())
+			t.Errorf("want: %s", test.want)
+		}
+	}
+}
+
+func TestWriter_Reset(t *testing.T) {
+	n := New()
+	_, _ = n.WriteString("one")
+	child := WithTitledParent(n, []byte("title"))
+	_, _ = child.WriteString("two")
+
+	n.Reset()
+
+	if n.Buf.Len() != 0 || child.Buf.Len() != 0 {
+		t.Error("could not reset the content of the writers")
+	}
+	if len(n.Children) != 1 || string(child.Title) != "title" {
+		t.Error("could not keep children and titles on reset")
+	}
+}
+
 func TestWriterRaceConditions(t *testing.T) {
 	if !raceEnabled {
 		t.Skip("race detector is not enabled")
@@ -234,3 +323,448 @@ func TestWriterRaceConditions(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestWriter_Close(t *testing.T) {
+	tests := map[string]*Writer{
+		"buffered writer":  New(),
+		"streaming writer": NewStream(ioutil.Discard),
+	}
+
+	for name, n := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := n.Close(); err != nil {
+				t.Errorf("could not close the writer: %s", err)
+			}
+			if _, err := n.WriteString("closed"); err != ErrClosed {
+				t.Errorf("could not reject write on closed writer: %v", err)
+			}
+			if err := n.Close(); err != ErrClosed {
+				t.Errorf("could not reject closing twice: %v", err)
+			}
+			if _, err := WithParent(n).WriteString("closed"); err != ErrClosed {
+				t.Errorf("could not reject write on child of closed writer: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriter_Done(t *testing.T) {
+	n := New()
+	a := WithParent(n)
+	b := WithParent(a)
+
+	isDone := func(n *Writer) bool {
+		select {
+		case <-n.Done():
+			return true
+		default:
+			return false
+		}
+	}
+
+	steps := []struct {
+		close *Writer
+		want  [3]bool
+	}{
+		{close: n, want: [3]bool{false, false, false}},
+		{close: b, want: [3]bool{false, false, true}},
+		{close: a, want: [3]bool{true, true, true}},
+	}
+
+	for i, step := range steps {
+		if err := step.close.Close(); err != nil {
+			t.Errorf("step %d: could not close: %s", i, err)
+		}
+		got := [3]bool{isDone(n), isDone(a), isDone(b)}
+		if got != step.want {
+			t.Errorf("step %d: could not match done writers: got %v, want %v", i, got, step.want)
+		}
+	}
+}
+
+func TestWriter_Write_lineBuffered(t *testing.T) {
+	tests := map[string]struct {
+		chunks []string
+		flush  bool
+		want   string
+	}{
+		"line split in chunks": {
+			chunks: []string{"a lo", "ng string", "!\n"},
+			want:   "    a long string!\n",
+		},
+		"incomplete line is held": {
+			chunks: []string{"one\ntw", "o\nthr"},
+			want:   "    one\n    two\n",
+		},
+		"incomplete line is flushed": {
+			chunks: []string{"one\ntw", "o\nthr"},
+			flush:  true,
+			want:   "    one\n    two\n    thr\n",
+		},
+		"empty lines": {
+			chunks: []string{"\n", "\none\n"},
+			want:   "    \n    \n    one\n",
+		},
+		"nothing to flush": {
+			chunks: []string{"one\n"},
+			flush:  true,
+			want:   "    one\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := WithParent(New(LineBuffered()))
+			for _, chunk := range test.chunks {
+				i, err := n.WriteString(chunk)
+				if err != nil {
+					t.Errorf("could not write chunk: %s", err)
+				}
+				if i != len(chunk) {
+					t.Error("could not match bytes written")
+					t.Errorf("got: %d", i)
+					t.Errorf("want: %d", len(chunk))
+				}
+			}
+			if test.flush {
+				if err := n.Flush(); err != nil {
+					t.Errorf("could not flush: %s", err)
+				}
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write content to the writer: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match string written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWriter_Close_lineBuffered(t *testing.T) {
+	n := New(LineBuffered())
+	_, _ = n.WriteString("held")
+	if err := n.Close(); err != nil {
+		t.Errorf("could not close: %s", err)
+	}
+	if want := "held\n"; n.Buf.String() != want {
+		t.Errorf("could not flush on close: got %q, want %q", n.Buf.String(), want)
+	}
+}
+
+func TestWriter_Reset_lineBuffered(t *testing.T) {
+	n := New(LineBuffered())
+	_, _ = n.WriteString("partial")
+	n.Reset()
+	_, _ = n.WriteString("new\n")
+
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "new\n"; w.String() != want {
+		t.Errorf("could not discard the held data: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestWriter_Depth(t *testing.T) {
+	n := New(Indent(" "))
+	for i := 0; i < 300; i++ {
+		n = WithParent(n)
+	}
+	if n.Depth != 300 {
+		t.Errorf("could not match depth: got %d, want 300", n.Depth)
+	}
+	_, _ = n.WriteString("deep")
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		t.Errorf("could not write content to the writer: %s", err)
+	}
+	if want := strings.Repeat(" ", 300) + "deep\n"; w.String() != want {
+		t.Errorf("could not match indentation: got %d bytes, want %d", w.Len(), len(want))
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	build := func(opts ...Option) *Writer {
+		n := New(opts...)
+		_, _ = n.WriteString("0")
+		parent := n
+		for _, title := range []string{"1", "2", "3"} {
+			parent = WithTitledParent(parent, []byte(title))
+			_, _ = parent.WriteString(title)
+		}
+		_, _ = WithTitledParent(WithTitledParent(n, []byte("1b")), []byte("2b")).WriteString("2b")
+		return n
+	}
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"no limit": {
+			nest: build(),
+			want: "0\n1\n    1\n    2\n        2\n        3\n            3\n1b\n    2b\n        2b\n",
+		},
+		"error": {
+			nest: build(MaxDepth(2, DepthError)),
+			want: "0\n1\n    1\n    2\n        2\n        3\n1b\n    2b\n        2b\n",
+		},
+		"clamp": {
+			nest: build(MaxDepth(1, DepthClamp)),
+			want: "0\n1\n    1\n    2\n    2\n    3\n    3\n1b\n    2b\n    2b\n",
+		},
+		"collapse": {
+			nest: build(MaxDepth(1, DepthCollapse)),
+			want: "0\n1\n    1\n    … (2 more levels)\n1b\n    … (1 more level)\n",
+		},
+		"collapse beyond the deepest writer": {
+			nest: build(MaxDepth(3, DepthCollapse)),
+			want: "0\n1\n    1\n    2\n        2\n        3\n            3\n1b\n    2b\n        2b\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+
+	deep := WithParent(WithParent(New(MaxDepth(1, DepthError))))
+	if _, err := deep.WriteString("too deep"); err != ErrMaxDepth {
+		t.Errorf("could not reject write beyond the maximum depth: %v", err)
+	}
+}
+
+func TestMaxDepth_drain(t *testing.T) {
+	n := New(MaxDepth(1, DepthCollapse))
+	collapsed := WithParent(WithParent(n))
+	for i := 0; i < 1000; i++ {
+		_, _ = collapsed.WriteString("hello")
+		if _, err := n.Drain(ioutil.Discard); err != nil {
+			t.Fatalf("could not drain: %s", err)
+		}
+	}
+	if collapsed.Buf.Len() != 0 {
+		t.Errorf("could not drain the collapsed writers: %d bytes left", collapsed.Buf.Len())
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	build := func(opts ...Option) *Writer {
+		n := New(opts...)
+		_ = WithTitledParent(n, []byte("c"), Key(1))
+		_ = WithTitledParent(n, []byte("a"), Key(2))
+		_ = WithTitledParent(n, []byte("b"), Key(1))
+		_ = WithParent(n, Key(0))
+		return n
+	}
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"by creation": {
+			nest: build(),
+			want: "c\na\nb\n",
+		},
+		"by key": {
+			nest: build(SortBy(ByKey)),
+			want: "c\nb\na\n",
+		},
+		"by title": {
+			nest: build(SortBy(ByTitle)),
+			want: "a\nb\nc\n",
+		},
+		"by key and numbered": {
+			nest: build(SortBy(ByKey), Numbered()),
+			want: "1 c\n2 b\n3 a\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match sorted content")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+
+	n := build(SortBy(ByKey), Numbered())
+	w := &bytes.Buffer{}
+	if _, err := n.Children[1].WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "3 a\n"; w.String() != want {
+		t.Errorf("could not number a sorted sub tree: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestWriter_Write_allocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector is enabled")
+	}
+
+	n := WithParent(WithParent(New()))
+	line := []byte("a log line")
+	_, _ = n.Write(line)
+	n.Reset()
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, _ = n.Write(line)
+		_, _ = n.WriteString("a log line")
+		n.Reset()
+	}); allocs != 0 {
+		t.Errorf("could not write without allocating: got %v allocations", allocs)
+	}
+}
+
+func TestWriter_Write_streamAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector is enabled")
+	}
+
+	n := WithParent(WithParent(NewStream(ioutil.Discard)))
+	line := []byte("a log line")
+	_, _ = n.Write(line)
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, _ = n.Write(line)
+	}); allocs != 0 {
+		t.Errorf("could not stream without allocating: got %v allocations", allocs)
+	}
+}
+
+func BenchmarkWriter_Write(b *testing.B) {
+	n := WithParent(WithParent(New()))
+	line := []byte("a log line of a reasonable length, as written by a logger")
+	b.ReportAllocs()
+	b.SetBytes(int64(len(line)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%1024 == 0 {
+			n.Reset()
+		}
+		_, _ = n.Write(line)
+	}
+}
+
+func BenchmarkWriter_WriteString(b *testing.B) {
+	n := WithParent(WithParent(New()))
+	line := "a log line of a reasonable length, as written by a logger"
+	b.ReportAllocs()
+	b.SetBytes(int64(len(line)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%1024 == 0 {
+			n.Reset()
+		}
+		_, _ = n.WriteString(line)
+	}
+}
+
+func BenchmarkWriter_Write_lineBuffered(b *testing.B) {
+	n := WithParent(WithParent(New(LineBuffered())))
+	line := []byte("a log line of a reasonable length, as written by a logger\n")
+	b.ReportAllocs()
+	b.SetBytes(int64(len(line)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%1024 == 0 {
+			n.Reset()
+		}
+		_, _ = n.Write(line)
+	}
+}
+
+func BenchmarkWriter_WriteTo(b *testing.B) {
+	n := New()
+	for i := 0; i < 10; i++ {
+		child := WithTitledParent(n, []byte("section"))
+		for j := 0; j < 100; j++ {
+			_, _ = child.WriteString("a log line of a reasonable length, as written by a logger")
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = n.WriteTo(ioutil.Discard)
+	}
+}
+
+func TestOmitEmpty(t *testing.T) {
+	build := func(opts ...Option) *Writer {
+		n := New(opts...)
+		full := WithTitledParent(n, []byte("full"))
+		_, _ = full.WriteString("content")
+		_ = WithTitledParent(full, []byte("empty child"))
+		_ = WithTitledParent(n, []byte("empty"))
+		nested := WithTitledParent(n, []byte("nested"))
+		_, _ = WithParent(WithTitledParent(nested, []byte("deep"))).WriteString("deep content")
+		_ = WithTitledParent(WithTitledParent(n, []byte("empty parent")), []byte("empty"))
+		_ = WithParent(n)
+		return n
+	}
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"kept": {
+			nest: build(),
+			want: "full\n    content\n    empty child\nempty\nnested\n    deep\n            deep content\nempty parent\n    empty\n",
+		},
+		"omitted": {
+			nest: build(OmitEmpty()),
+			want: "full\n    content\nnested\n    deep\n            deep content\n",
+		},
+		"marked": {
+			nest: build(MarkEmpty("(empty)")),
+			want: "full\n    content\n    empty child (empty)\nempty (empty)\nnested\n    deep\n            deep content\nempty parent (empty)\n",
+		},
+		"omitted and numbered": {
+			nest: build(OmitEmpty(), Numbered()),
+			want: "1 full\n    content\n2 nested\n    2.1 deep\n            deep content\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+
+	n := build(OmitEmpty(), Numbered())
+	w := &bytes.Buffer{}
+	if _, err := n.Children[2].WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "2 nested\n    2.1 deep\n            deep content\n"; w.String() != want {
+		t.Errorf("could not number a sub tree: got %q, want %q", w.String(), want)
+	}
+}
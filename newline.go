@@ -0,0 +1,88 @@
+package nest
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// CRLF makes the text renderers, WriteTo, Drain, Tree, Markdown, HTML and streaming Writers,
+// end the lines with a carriage return and a line feed, as Windows tools expect.
+// The lines are stored with a line feed only regardless.
+func CRLF() Option {
+	return func(n *Writer) {
+		n.opts.crlf = true
+	}
+}
+
+// CollapseCR stores the lines rewritten with carriage returns, such as the ones of a progress bar,
+// in their final state, the characters following a carriage return overwriting the ones
+// at the start of the line as they do on a terminal.
+// Without it, the carriage returns are kept, and the text renderers indent the content following them.
+// The carriage returns of CRLF line endings are always removed, from the content and the titles.
+// It is mostly useful along LineBuffered, so that all the updates of a line are collapsed.
+func CollapseCR() Option {
+	return func(n *Writer) {
+		n.opts.collapseCR = true
+	}
+}
+
+// normalize returns p with the CRLF line endings replaced by LF, as well as a carriage return
+// ending p, and with the lines collapsed as CollapseCR does if it is set.
+// p is returned as it is when it has no carriage returns.
+func (o options) normalize(p []byte) []byte {
+	if bytes.IndexByte(p, '\r') < 0 {
+		return p
+	}
+
+	var normalized []byte
+	for len(p) > 0 {
+		line, end := p, len(p)
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line, end = p[:i], i+1
+		}
+		content := bytes.TrimSuffix(line, []byte{'\r'})
+		if o.collapseCR {
+			content = overlay(content)
+		}
+		normalized = append(normalized, content...)
+		normalized = append(normalized, p[len(line):end]...)
+		p = p[end:]
+	}
+	return normalized
+}
+
+// overlay returns line as a terminal displays it, the characters following
+// a carriage return overwriting the ones at the start of the line.
+// An escape sequence overwrites, and is overwritten, as a single character.
+func overlay(line []byte) []byte {
+	if bytes.IndexByte(line, '\r') < 0 {
+		return line
+	}
+
+	var cells [][]byte
+	column := 0
+	for len(line) > 0 {
+		if line[0] == '\r' {
+			column = 0
+			line = line[1:]
+			continue
+		}
+		size := escapeLen(line)
+		if size == 0 {
+			_, size = utf8.DecodeRune(line)
+		}
+		if column < len(cells) {
+			cells[column] = line[:size]
+		} else {
+			cells = append(cells, line[:size])
+		}
+		column++
+		line = line[size:]
+	}
+	return bytes.Join(cells, nil)
+}
+
+// crlf returns b with its line feeds preceded by carriage returns.
+func crlf(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{'\n'}, []byte("\r\n"))
+}
@@ -0,0 +1,26 @@
+package nest
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleCollapseCR() {
+	n := New(CollapseCR(), LineBuffered())
+	download := WithTitledParent(n, []byte("download"))
+	for i := 0; i <= 100; i += 25 {
+		if _, err := fmt.Fprintf(download, "\r%3d%%", i); err != nil {
+			panic(err)
+		}
+	}
+	if err := download.Close(); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// download
+	//     100%
+}
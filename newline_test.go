@@ -0,0 +1,146 @@
+package nest
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriter_Write_carriageReturns(t *testing.T) {
+	tests := map[string]struct {
+		opts   []Option
+		chunks []string
+		want   string
+	}{
+		"crlf": {
+			chunks: []string{"one\r\ntwo\r\nthree"},
+			want:   "    one\n    two\n    three\n",
+		},
+		"crlf split in chunks": {
+			opts:   []Option{LineBuffered()},
+			chunks: []string{"one\r", "\ntwo\r\n"},
+			want:   "    one\n    two\n",
+		},
+		"bare carriage returns are indented": {
+			chunks: []string{"10%\r50%\r100%"},
+			want:   "    10%\r    50%\r    100%\n",
+		},
+		"collapsed": {
+			opts:   []Option{CollapseCR()},
+			chunks: []string{"10%\r50%\r100%\r\nnext"},
+			want:   "    100%\n    next\n",
+		},
+		"collapsed over a longer line": {
+			opts:   []Option{CollapseCR()},
+			chunks: []string{"downloading\rdone"},
+			want:   "    doneloading\n",
+		},
+		"collapsed progress written in chunks": {
+			opts:   []Option{CollapseCR(), LineBuffered()},
+			chunks: []string{"[   ] 0%\r", "[#  ] 33%\r", "[## ] 66%\r", "[###] 100%\r", "\n"},
+			want:   "    [###] 100%\n",
+		},
+		"collapsed wide characters": {
+			opts:   []Option{CollapseCR()},
+			chunks: []string{"進捗 10%\r進捗 99%"},
+			want:   "    進捗 99%\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := WithParent(New(test.opts...))
+			for _, chunk := range test.chunks {
+				if _, err := n.WriteString(chunk); err != nil {
+					t.Fatalf("could not write: %s", err)
+				}
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWithTitledParent_carriageReturns(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		want string
+	}{
+		"lf": {
+			want: "a\nb\n    content\n",
+		},
+		"crlf": {
+			opts: []Option{CRLF()},
+			want: "a\r\nb\r\n    content\r\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := New(test.opts...)
+			_, _ = WithTitledParent(n, []byte("a\r\nb\r")).WriteString("content")
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match title written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestCRLF(t *testing.T) {
+	n := New(CRLF())
+	child := WithTitledParent(n, []byte("title"))
+	_, _ = child.WriteString("one\r\ntwo")
+
+	if got, want := child.Buf.String(), "one\ntwo\n"; got != want {
+		t.Errorf("could not store line feeds: got %q, want %q", got, want)
+	}
+
+	tests := map[string]struct {
+		writer io.WriterTo
+		want   string
+	}{
+		"writer": {
+			writer: n,
+			want:   "title\r\n    one\r\n    two\r\n",
+		},
+		"tree": {
+			writer: Tree{Writer: n},
+			want:   "title\r\n├── one\r\n└── two\r\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			i, err := test.writer.WriteTo(w)
+			if err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want || i != int64(w.Len()) {
+				t.Error("could not match content written")
+				t.Errorf("got: %q, %d bytes", w.String(), i)
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+
+	w := &bytes.Buffer{}
+	s := NewStream(w, CRLF())
+	_, _ = WithTitledParent(s, []byte("title")).WriteString("one\r\ntwo")
+	if want := "title\r\n    one\r\n    two\r\n"; w.String() != want {
+		t.Errorf("could not match stream: got %q, want %q", w.String(), want)
+	}
+}
@@ -0,0 +1,152 @@
+package nest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A NumberStyle formats the numbers of one level of an outline.
+type NumberStyle int
+
+const (
+	// Decimal numbers with 1, 2, 3.
+	Decimal NumberStyle = iota
+	// LowerAlpha numbers with a, b, c, and then aa, ab.
+	LowerAlpha
+	// UpperAlpha numbers with A, B, C, and then AA, AB.
+	UpperAlpha
+	// LowerRoman numbers with i, ii, iii.
+	LowerRoman
+	// UpperRoman numbers with I, II, III.
+	UpperRoman
+)
+
+// romans are the roman numerals with their values, in descending order.
+var romans = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// Numbered prefixes the titles with their outline number, such as 1, 1.2 or 1.2.3,
+// counting the titled Writers only: the ones below an untitled Writer are numbered
+// as if they were Children of its parent.
+// The numbers are computed at render time from the position of each Writer among its siblings,
+// so they are right even when the children are created concurrently.
+// The styles are used for each level in order, the last one for all the deeper levels;
+// without styles all the levels are Decimal.
+func Numbered(styles ...NumberStyle) Option {
+	if len(styles) == 0 {
+		styles = []NumberStyle{Decimal}
+	}
+	return func(n *Writer) {
+		n.opts.numbering = styles
+	}
+}
+
+// format returns i formatted in the style.
+func (s NumberStyle) format(i int) string {
+	switch s {
+	case LowerAlpha, UpperAlpha:
+		var b []byte
+		for ; i > 0; i = (i - 1) / 26 {
+			b = append([]byte{byte('a' + (i-1)%26)}, b...)
+		}
+		if s == UpperAlpha {
+			return strings.ToUpper(string(b))
+		}
+		return string(b)
+	case LowerRoman, UpperRoman:
+		if i >= 4000 {
+			return strconv.Itoa(i)
+		}
+		var b strings.Builder
+		for _, r := range romans {
+			for ; i >= r.value; i -= r.value {
+				b.WriteString(r.numeral)
+			}
+		}
+		if s == LowerRoman {
+			return strings.ToLower(b.String())
+		}
+		return b.String()
+	default:
+		return strconv.Itoa(i)
+	}
+}
+
+// numberedTitle returns the lines of the Title, the first one prefixed by the outline number.
+func (n *Writer) numberedTitle(number []int) [][]byte {
+	lines := n.titleLines()
+	if len(lines) == 0 || len(n.opts.numbering) == 0 || len(number) == 0 {
+		return lines
+	}
+
+	parts := make([]string, len(number))
+	for i, num := range number {
+		style := n.opts.numbering[len(n.opts.numbering)-1]
+		if i < len(n.opts.numbering) {
+			style = n.opts.numbering[i]
+		}
+		parts[i] = style.format(num)
+	}
+	lines[0] = append([]byte(strings.Join(parts, ".")+" "), lines[0]...)
+	return lines
+}
+
+// numbering returns the outline number of n, from the position of its titled ancestors,
+// and the number of titled Writers numbered before the Children of n at their level.
+// The titled Writers below untitled ones are numbered at the level of the nearest titled ancestor,
+// the number of an untitled Writer being the one of that ancestor.
+func (n *Writer) numbering() (number []int, titled int) {
+	scope := n.parentWriter()
+	if scope == nil {
+		return nil, 0
+	}
+	for len(scope.Title) == 0 && scope.parentWriter() != nil {
+		scope = scope.parentWriter()
+	}
+	number, _ = scope.numbering()
+	i, _ := scope.preceding(n)
+	if len(n.Title) > 0 {
+		return append(number[:len(number):len(number)], i+1), 0
+	}
+	return number, i
+}
+
+// preceding returns the number of titled Writers rendered before target at the level of n,
+// looking into the untitled Children, and whether target was found.
+func (n *Writer) preceding(target *Writer) (int, bool) {
+	i := 0
+	for _, child := range n.ordered() {
+		if child == target {
+			return i, true
+		}
+		if child.omitted() {
+			continue
+		}
+		if len(child.Title) > 0 {
+			i++
+			continue
+		}
+		j, found := child.preceding(target)
+		i += j
+		if found {
+			return i, true
+		}
+	}
+	return i, false
+}
+
+// scope returns the Writer whose level the titled Children of n are numbered at,
+// that is the nearest titled one from n, or the root.
+// The streaming Writers are never moved, so their parent is read without the mutex.
+func (n *Writer) scope() *Writer {
+	for len(n.Title) == 0 && n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleNumbered() {
+	base := New(Numbered(Decimal, LowerAlpha))
+
+	install := WithTitledParent(base, []byte("Install"))
+	_ = WithTitledParent(install, []byte("Download"))
+	_ = WithTitledParent(install, []byte("Verify"))
+
+	run := WithTitledParent(base, []byte("Run"))
+	if _, err := run.WriteString("./app"); err != nil {
+		panic(err)
+	}
+
+	if _, err := base.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 1 Install
+	//     1.a Download
+	//     1.b Verify
+	// 2 Run
+	//     ./app
+}
+
+func ExampleNumbered_stream() {
+	base := NewSimpleStream(os.Stdout, Numbered())
+
+	first := base.Child("First")
+	nested := first.Child("Nested")
+	nested.Write("content")
+	if err := nested.Close(); err != nil {
+		panic(err)
+	}
+	if err := first.Close(); err != nil {
+		panic(err)
+	}
+	base.Child("Second").Write("content")
+
+	// Output:
+	// 1 First
+	//     1.1 Nested
+	//         content
+	// 2 Second
+	//     content
+}
@@ -0,0 +1,108 @@
+package nest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestNumberStyle_format(t *testing.T) {
+	tests := map[string]struct {
+		style NumberStyle
+		i     int
+		want  string
+	}{
+		"decimal":               {style: Decimal, i: 12, want: "12"},
+		"lower alpha":           {style: LowerAlpha, i: 2, want: "b"},
+		"lower alpha last":      {style: LowerAlpha, i: 26, want: "z"},
+		"lower alpha two digit": {style: LowerAlpha, i: 28, want: "ab"},
+		"upper alpha":           {style: UpperAlpha, i: 703, want: "AAA"},
+		"lower roman":           {style: LowerRoman, i: 4, want: "iv"},
+		"upper roman":           {style: UpperRoman, i: 1994, want: "MCMXCIV"},
+		"roman out of range":    {style: UpperRoman, i: 4000, want: "4000"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.style.format(test.i); got != test.want {
+				t.Error("could not match number")
+				t.Errorf("got: %s", got)
+				t.Errorf("want: %s", test.want)
+			}
+		})
+	}
+}
+
+func TestNumbered(t *testing.T) {
+	build := func(opts ...Option) *Writer {
+		n := New(opts...)
+		a := WithTitledParent(n, []byte("a"))
+		_, _ = WithTitledParent(a, []byte("a.a")).WriteString("content")
+		untitled := WithParent(a)
+		_, _ = WithTitledParent(untitled, []byte("u.a")).WriteString("content")
+		_, _ = WithTitledParent(a, []byte("a.b")).WriteString("content")
+		_, _ = WithTitledParent(n, []byte("b\nsecond line")).WriteString("content")
+		return n
+	}
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"decimal": {
+			nest: build(Numbered()),
+			want: "1 a\n    1.1 a.a\n        content\n        1.2 u.a\n            content\n    1.3 a.b\n        content\n2 b\nsecond line\n    content\n",
+		},
+		"styles per level": {
+			nest: build(Numbered(UpperRoman, LowerAlpha)),
+			want: "I a\n    I.a a.a\n        content\n        I.b u.a\n            content\n    I.c a.b\n        content\nII b\nsecond line\n    content\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match numbered content")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+
+	sub := build(Numbered()).Children[0].Children[2]
+	w := &bytes.Buffer{}
+	if _, err := sub.WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "    1.3 a.b\n        content\n"; w.String() != want {
+		t.Errorf("could not number a sub tree: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestNumbered_concurrent(t *testing.T) {
+	n := New(Numbered())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = WithTitledParent(n, []byte("section"))
+		}()
+	}
+	wg.Wait()
+
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	lines := bytes.Split(bytes.TrimSuffix(w.Bytes(), []byte{'\n'}), []byte{'\n'})
+	for i, line := range lines {
+		if want := Decimal.format(i+1) + " section"; string(line) != want {
+			t.Errorf("could not number concurrent sections: got %q, want %q", line, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package nest
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+// ErrMaxDepth is returned when writing to a Writer deeper than the maximum depth
+// set with the DepthError policy.
+var ErrMaxDepth = errors.New("nest: Writer is deeper than the maximum depth")
+
+// A DepthPolicy tells how the Writers deeper than the maximum depth are handled.
+type DepthPolicy int
+
+const (
+	// DepthError makes the writes to the Writers deeper than the maximum depth fail with ErrMaxDepth.
+	DepthError DepthPolicy = iota
+	// DepthClamp indents the Writers deeper than the maximum depth as the ones at the maximum depth.
+	DepthClamp
+	// DepthCollapse renders the Writers deeper than the maximum depth as a single line
+	// telling how many levels are omitted, such as "… (3 more levels)".
+	DepthCollapse
+)
+
+// cachedDepth is the number of levels whose indentation is computed once,
+// instead of on each use.
+const cachedDepth = 32
+
+var (
+	// defaultIndent is the indentation unit used when none is configured.
+	defaultIndent = []byte("    ")
+	// defaultIndents holds the default indentation of the first cachedDepth levels.
+	defaultIndents = bytes.Repeat(defaultIndent, cachedDepth)
+)
+
+// options holds the settings a Writer passes down to its children.
+type options struct {
+	indent       []byte
+	indents      []byte
+	lineBuffered bool
+	maxDepth     int
+	depthPolicy  DepthPolicy
+	numbering    []NumberStyle
+	order        Order
+	omitEmpty    bool
+	emptyMarker  []byte
+	limited      bool
+	limitBytes   bool
+	limitHead    int
+	limitTail    int
+	wrap         int
+	hanging      []byte
+	color        ColorMode
+	palette      []Style
+	titleStyle   Style
+	stripANSI    bool
+	crlf         bool
+	collapseCR   bool
+	tabWidth     int
+}
+
+// prefix returns the indentation for the given depth.
+// It is shared by all the callers and must not be modified.
+func (o options) prefix(depth int) []byte {
+	if o.beyond(depth, DepthClamp) {
+		depth = o.maxDepth
+	}
+	indent, indents := o.indent, o.indents
+	if indent == nil {
+		indent, indents = defaultIndent, defaultIndents
+	}
+	if n := depth * len(indent); n <= len(indents) {
+		return indents[:n:n]
+	}
+	return bytes.Repeat(indent, depth)
+}
+
+// beyond reports whether depth is deeper than the maximum depth set with the given policy.
+func (o options) beyond(depth int, policy DepthPolicy) bool {
+	return o.maxDepth > 0 && o.depthPolicy == policy && depth > o.maxDepth
+}
+
+// An Option configures a Writer.
+// Options given to New apply to the whole tree,
+// options given to WithParent or WithTitledParent apply to the new Writer and its children.
+type Option func(*Writer)
+
+// Indent sets the string used to indent each level of depth.
+// The default is four spaces.
+func Indent(s string) Option {
+	return func(n *Writer) {
+		n.opts.indent = []byte(s)
+		n.opts.indents = bytes.Repeat(n.opts.indent, cachedDepth)
+	}
+}
+
+// LineBuffered makes Write hold the data following the last new line of p,
+// until a later write completes the line or Flush or Close is called,
+// so that content written in chunks, as fmt.Fprint, io.Copy or a log.Logger do,
+// is split and indented at the start of real lines only.
+// In this mode Write returns len(p), as the io.Writer interface requires.
+func LineBuffered() Option {
+	return func(n *Writer) {
+		n.opts.lineBuffered = true
+	}
+}
+
+// MaxDepth limits the depth of the Writers, the Writers deeper than max
+// being handled according to the policy.
+// A max lower than one removes the limit.
+// Streaming Writers ignore the DepthCollapse policy and write the deeper Writers in full,
+// as the number of levels omitted is not known yet when their content is written through.
+func MaxDepth(max int, policy DepthPolicy) Option {
+	return func(n *Writer) {
+		n.opts.maxDepth = max
+		n.opts.depthPolicy = policy
+	}
+}
+
+// OmitEmpty leaves out of the rendering the Writers holding no content,
+// whose descendants hold no content either, so that sections which turned out
+// to be empty do not leave a dangling title.
+// Streaming Writers write their titles regardless.
+func OmitEmpty() Option {
+	return func(n *Writer) {
+		n.opts.omitEmpty = true
+	}
+}
+
+// MarkEmpty works like OmitEmpty, but it keeps the titles of the empty Writers,
+// followed by marker, such as "(empty)".
+// Empty Writers without a title are left out as OmitEmpty does.
+func MarkEmpty(marker string) Option {
+	return func(n *Writer) {
+		n.opts.omitEmpty = true
+		n.opts.emptyMarker = []byte(marker)
+	}
+}
+
+// An Order tells how the children of a Writer are sorted when rendered.
+type Order int
+
+const (
+	// ByCreation renders the children in the order they were created.
+	ByCreation Order = iota
+	// ByKey renders the children sorted by the key given with Key,
+	// the ones with the same key in the order they were created.
+	ByKey
+	// ByTitle renders the children sorted by title,
+	// the ones with the same title in the order they were created.
+	ByTitle
+)
+
+// SortBy sets the order the children are rendered in, so that children created concurrently
+// produce the same output whatever the order the goroutines were scheduled in.
+// Streaming Writers write their children in the order they were created regardless.
+func SortBy(order Order) Option {
+	return func(n *Writer) {
+		n.opts.order = order
+	}
+}
+
+// Key sets the key the Writer is sorted by among its siblings, when their parent is sorted ByKey.
+// Unlike the other options, it only applies to the new Writer, and not to its children.
+// Writers created without a key have a zero key.
+func Key(key int) Option {
+	return func(n *Writer) {
+		n.key = key
+	}
+}
+
+// sort sorts the children in the order set with SortBy.
+func (o options) sort(children []*Writer) {
+	switch o.order {
+	case ByKey:
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].key < children[j].key
+		})
+	case ByTitle:
+		sort.SliceStable(children, func(i, j int) bool {
+			return bytes.Compare(children[i].Title, children[j].Title) < 0
+		})
+	}
+}
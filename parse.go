@@ -0,0 +1,105 @@
+package nest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrEmptyIndent is returned when parsing with an empty indent unit.
+var ErrEmptyIndent = errors.New("nest: cannot parse with an empty indent unit")
+
+// Parse reads indented text, as written by Writer.WriteTo, and rebuilds the Writer tree.
+// The options are applied to the returned Writer;
+// the indent unit set with Indent is the one used to measure the depth of each line.
+//
+// A line followed by a deeper one becomes the title of a new child,
+// and so does a line following the children of its Writer,
+// since the content of a Writer is always written before its children.
+// Any other line is written as content.
+// A line nested one level below a content line is placed in an untitled child, as WithParent creates it.
+// A line is never nested more than one level below the previous one: the indentation beyond that,
+// as well as a partial indent unit, is kept as content, such as the leading spaces of tabwriter output.
+// Empty lines belong to the last Writer.
+func Parse(r io.Reader, opts ...Option) (*Writer, error) {
+	root := New(opts...)
+	unit := root.opts.indent
+	if unit == nil {
+		unit = defaultIndent
+	}
+	if len(unit) == 0 {
+		return nil, ErrEmptyIndent
+	}
+
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]int, len(lines))
+	previous := 0
+	for i, line := range lines {
+		if len(line) == 0 {
+			levels[i] = -1
+			continue
+		}
+		for levels[i] <= previous && bytes.HasPrefix(line, unit) {
+			line = line[len(unit):]
+			levels[i]++
+		}
+		previous = levels[i]
+		lines[i] = line
+	}
+
+	stack := []*Writer{root}
+	for i, line := range lines {
+		level := levels[i]
+		if level < 0 {
+			stack[len(stack)-1].writeLine(line)
+			continue
+		}
+
+		for len(stack) <= level {
+			stack = append(stack, WithParent(stack[len(stack)-1]))
+		}
+		stack = stack[:level+1]
+
+		n := stack[level]
+		if nextLevel(levels, i) > level || len(n.Children) > 0 {
+			stack = append(stack, WithTitledParent(n, line))
+			continue
+		}
+		n.writeLine(line)
+	}
+
+	return root, nil
+}
+
+// readLines reads all the lines of r, without their line terminator.
+func readLines(r io.Reader) ([][]byte, error) {
+	var lines [][]byte
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, bytes.TrimSuffix(line, []byte{'\n'}))
+		}
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextLevel returns the level of the first non-empty line after the i-th one, or -1.
+func nextLevel(levels []int, i int) int {
+	for _, level := range levels[i+1:] {
+		if level >= 0 {
+			return level
+		}
+	}
+	return -1
+}
@@ -0,0 +1,47 @@
+package nest
+
+import (
+	"os"
+	"strings"
+)
+
+func ExampleParse() {
+	report := `Build
+  compiled 3 packages
+Tests
+  Package a
+    ok
+  Package b
+    fail
+`
+
+	n, err := Parse(strings.NewReader(report), Indent("  "))
+	if err != nil {
+		panic(err)
+	}
+	if _, err := (Tree{Writer: n}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Build
+	// └── compiled 3 packages
+	// Tests
+	// ├── Package a
+	// │   └── ok
+	// └── Package b
+	//     └── fail
+}
+
+func ExampleParse_leadingWhitespace() {
+	n, err := Parse(strings.NewReader("Section\n    one\n      two\n"))
+	if err != nil {
+		panic(err)
+	}
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Section
+	//     one
+	//       two
+}
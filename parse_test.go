@@ -0,0 +1,127 @@
+package nest
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []Option
+	}{
+		"titled children": {
+			input: "root\nSection 1\n    one\n    Section 1.1\n        two\nSection 2\n",
+		},
+		"titled children without content": {
+			input: "Section 1\n    Section 1.1\nSection 2\n",
+		},
+		"untitled children": {
+			input: "root\n    child\nSection 1\n    one\n        child\n",
+		},
+		"leading whitespace": {
+			input: "Section 1\n     leading space\n    one\n      two\n            deeper\n",
+		},
+		"empty lines": {
+			input: "root\n\nSection 1\n    one\n    \n    two\n",
+		},
+		"tab indentation": {
+			input: "Section 1\n\tone\n\tSection 1.1\n\t\t two\n",
+			opts:  []Option{Indent("\t")},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := Parse(strings.NewReader(test.input), test.opts...)
+			if err != nil {
+				t.Fatalf("could not parse: %s", err)
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Fatalf("could not write the parsed writer: %s", err)
+			}
+			if w.String() != test.input {
+				t.Error("could not match the parsed content")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.input)
+			}
+		})
+	}
+}
+
+func TestParse_structure(t *testing.T) {
+	n, err := Parse(strings.NewReader("root\nSection 1\n    one\n    Section 1.1\n        two\nSection 2"))
+	if err != nil {
+		t.Fatalf("could not parse: %s", err)
+	}
+
+	if len(n.Children) != 2 {
+		t.Fatalf("could not match children: got %d, want 2", len(n.Children))
+	}
+	section := n.Children[0]
+	if string(section.Title) != "Section 1" || section.Depth != 1 || section.Buf.String() != "one\n" {
+		t.Errorf("could not match section: %q %d %q", section.Title, section.Depth, section.Buf.String())
+	}
+	if len(section.Children) != 1 || string(section.Children[0].Title) != "Section 1.1" || section.Children[0].Depth != 2 {
+		t.Error("could not match nested section")
+	}
+	if string(n.Children[1].Title) != "Section 2" {
+		t.Errorf("could not match last section: %q", n.Children[1].Title)
+	}
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	n := New()
+	section := WithTitledParent(n, []byte("Section 1"))
+	_, _ = section.WriteString(" leading space")
+	_, _ = section.WriteString("\tvet: report")
+	_, _ = WithTitledParent(section, []byte("Section 1.1")).WriteString("  two")
+
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	parsed, err := Parse(bytes.NewReader(w.Bytes()))
+	if err != nil {
+		t.Fatalf("could not parse: %s", err)
+	}
+
+	if got, want := parsed.Children[0].Buf.String(), section.Buf.String(); got != want {
+		t.Errorf("could not match content: got %q, want %q", got, want)
+	}
+	got := &bytes.Buffer{}
+	if _, err := parsed.WriteTo(got); err != nil {
+		t.Fatalf("could not write the parsed writer: %s", err)
+	}
+	if got.String() != w.String() {
+		t.Error("could not match the parsed content")
+		t.Errorf("got: %q", got.String())
+		t.Errorf("want: %q", w.String())
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		opts  []Option
+		err   error
+	}{
+		"empty indent unit": {
+			input: "root\n",
+			opts:  []Option{Indent("")},
+			err:   ErrEmptyIndent,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(test.input), test.opts...)
+			if !errors.Is(err, test.err) {
+				t.Errorf("could not match error: got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
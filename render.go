@@ -0,0 +1,321 @@
+package nest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// A view is a snapshot of a Writer and of its descendants, taken to render them
+// with the options of the tree applied.
+type view struct {
+	writer    *Writer
+	title     [][]byte
+	depth     int
+	raw       []byte
+	collapsed []byte
+	children  []*view
+
+	// empty is set when the title is followed by the marker set with MarkEmpty.
+	empty bool
+
+	// buffered, head and seq tell which part of raw is consumed when draining.
+	buffered int
+	head     int
+	seq      int
+}
+
+// view takes a view of n, numbering its title as it is numbered in the whole tree.
+func (n *Writer) view() *view {
+	number, titled := n.numbering()
+	return n.viewAt(number, &titled)
+}
+
+// viewAt takes a view of n, whose title has the given outline number;
+// titled counts the titled Writers numbered at the level of the untitled n.
+func (n *Writer) viewAt(number []int, titled *int) *view {
+	v := &view{
+		writer: n,
+		title:  n.numberedTitle(number),
+	}
+	n.mutex.Lock()
+	v.depth = n.Depth
+	v.raw = n.content()
+	v.buffered = n.Buf.Len()
+	if n.limit != nil {
+		v.head, v.seq = n.limit.head, n.limit.seq
+	}
+	children := append([]*Writer(nil), n.Children...)
+	n.mutex.Unlock()
+	n.opts.sort(children)
+
+	if n.marked() {
+		v.empty = true
+		return v
+	}
+	if n.opts.collapses(v.depth, children) {
+		v.collapsed = collapsed(children)
+		return v
+	}
+
+	if len(n.Title) > 0 {
+		titled = new(int)
+	}
+	for _, child := range children {
+		if child.omitted() {
+			continue
+		}
+		childNumber := number
+		if len(child.Title) > 0 {
+			*titled++
+			childNumber = append(number[:len(number):len(number)], *titled)
+		}
+		v.children = append(v.children, child.viewAt(childNumber, titled))
+	}
+	return v
+}
+
+// titleLines returns the lines of the title of the view,
+// the last one followed by the marker set with MarkEmpty when the view is empty.
+func (v *view) titleLines() [][]byte {
+	if !v.empty {
+		return v.title
+	}
+	lines := append([][]byte(nil), v.title...)
+	last := len(lines) - 1
+	lines[last] = append(append(append([]byte(nil), lines[last]...), ' '), v.writer.opts.emptyMarker...)
+	return lines
+}
+
+// terminalTitle works like titleLines, but with the styles set by escape sequences
+// confined to each line, for the renderers writing to a terminal.
+func (v *view) terminalTitle() [][]byte {
+	lines := v.titleLines()
+	if len(lines) == 0 {
+		return lines
+	}
+	return bytes.Split(confineStyles(bytes.Join(lines, []byte{'\n'})), []byte{'\n'})
+}
+
+// lines returns the lines of the view, without indentation,
+// followed by the one replacing the collapsed children.
+func (v *view) lines() [][]byte {
+	return v.collapse(split(v.raw))
+}
+
+// terminalLines works like lines, but with the tabs expanded and the styles
+// set by escape sequences confined to each line, for the renderers writing to a terminal.
+func (v *view) terminalLines() [][]byte {
+	return v.collapse(split(confineStyles(v.writer.opts.expandTabs(v.raw))))
+}
+
+// terminal returns raw, to be indented by prefix, with the tabs expanded, the long lines wrapped
+// and the styles set by escape sequences confined to each line.
+func (o options) terminal(raw, prefix []byte) []byte {
+	return confineStyles(o.wrapLines(o.expandTabs(raw), prefix))
+}
+
+// collapse returns lines followed by the one replacing the collapsed children, if any.
+func (v *view) collapse(lines [][]byte) [][]byte {
+	if v.collapsed != nil {
+		lines = append(lines, v.collapsed)
+	}
+	return lines
+}
+
+// writeTo writes the view indented, consuming the rendered content of the Writers when drain is set.
+func (v *view) writeTo(p *printer, drain bool) {
+	n := v.writer
+	content, title := v.styles(p)
+	titlePrefix := n.opts.titlePrefix(v.depth)
+	for _, line := range v.terminalTitle() {
+		p.line(titlePrefix, title.apply(line))
+	}
+
+	prefix := n.opts.prefix(v.depth)
+	p.indent(prefix, content.apply(n.opts.terminal(v.raw, prefix)))
+	if drain && p.err == nil {
+		n.mutex.Lock()
+		n.Buf.Next(v.buffered)
+		if n.limit != nil {
+			n.limit.drain(v, n.opts.limitHead)
+		}
+		n.mutex.Unlock()
+	}
+
+	if v.collapsed != nil {
+		p.line(n.opts.prefix(v.depth), v.collapsed)
+		if drain && p.err == nil {
+			for _, child := range n.children() {
+				child.discard()
+			}
+		}
+	}
+	for _, child := range v.children {
+		child.writeTo(p, drain)
+	}
+}
+
+// discard consumes the content of n and its descendants without rendering it,
+// as Drain does for the Writers collapsed by the DepthCollapse policy, which are never rendered.
+func (n *Writer) discard() {
+	n.mutex.Lock()
+	n.Buf.Reset()
+	n.limit = nil
+	children := append([]*Writer(nil), n.Children...)
+	n.mutex.Unlock()
+
+	for _, child := range children {
+		child.discard()
+	}
+}
+
+// omitted reports whether n is left out of the rendering by OmitEmpty.
+func (n *Writer) omitted() bool {
+	return n.opts.omitEmpty && (n.opts.emptyMarker == nil || len(n.Title) == 0) && n.empty()
+}
+
+// marked reports whether the title of n is followed by the marker set with MarkEmpty.
+func (n *Writer) marked() bool {
+	return n.opts.emptyMarker != nil && len(n.Title) > 0 && n.empty()
+}
+
+// empty reports whether n and its descendants hold no content.
+func (n *Writer) empty() bool {
+	n.mutex.Lock()
+	written := n.written()
+	children := append([]*Writer(nil), n.Children...)
+	n.mutex.Unlock()
+	if written {
+		return false
+	}
+	for _, child := range children {
+		if !child.empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// styles returns the styles of the content and of the title of the view,
+// which are empty when p does not apply them.
+func (v *view) styles(p *printer) (content, title Style) {
+	if !p.color {
+		return "", ""
+	}
+	return v.writer.styles(v.depth)
+}
+
+// titlePrefix returns the indentation of the Title of a Writer at the given depth,
+// one level above the content.
+func (o options) titlePrefix(depth int) []byte {
+	if depth == 0 {
+		return nil
+	}
+	return o.prefix(depth - 1)
+}
+
+// titleLines returns the lines of the Title.
+func (n *Writer) titleLines() [][]byte {
+	if len(n.Title) == 0 {
+		return nil
+	}
+	return bytes.Split(n.Title, []byte{'\n'})
+}
+
+// split returns the lines of raw, the content of a Writer.
+func split(raw []byte) [][]byte {
+	content := bytes.TrimSuffix(raw, []byte{'\n'})
+	if len(content) == 0 {
+		return nil
+	}
+	return bytes.Split(content, []byte{'\n'})
+}
+
+// collapses reports whether the children of a Writer at the given depth
+// are collapsed by the DepthCollapse policy.
+func (o options) collapses(depth int, children []*Writer) bool {
+	return len(children) > 0 && o.beyond(depth+1, DepthCollapse)
+}
+
+// collapsed returns the line replacing the collapsed children.
+func collapsed(children []*Writer) []byte {
+	levels := height(children)
+	if levels == 1 {
+		return []byte("… (1 more level)")
+	}
+	return []byte(fmt.Sprintf("… (%d more levels)", levels))
+}
+
+// height returns the number of levels of the deepest of the trees.
+func height(trees []*Writer) int {
+	h := 0
+	for _, tree := range trees {
+		if th := 1 + height(tree.children()); th > h {
+			h = th
+		}
+	}
+	return h
+}
+
+// A printer writes lines to an io.Writer,
+// keeping track of the bytes written and of the first error encountered.
+type printer struct {
+	w     io.Writer
+	color bool
+	crlf  bool
+	n     int64
+	err   error
+	buf   []byte
+}
+
+// printer returns a printer writing to w with the options of n.
+func (n *Writer) printer(w io.Writer) *printer {
+	return &printer{w: w, color: n.opts.colored(w), crlf: n.opts.crlf}
+}
+
+// line writes the concatenation of parts followed by a new line.
+func (p *printer) line(parts ...[]byte) {
+	if p.err != nil {
+		return
+	}
+	p.buf = p.buf[:0]
+	for _, part := range parts {
+		p.buf = append(p.buf, part...)
+	}
+	p.buf = append(p.buf, '\n')
+	p.write(p.buf)
+}
+
+// indent writes raw with prefix at the start of each of its lines,
+// and after each carriage return, so that a rewritten line is indented as well.
+func (p *printer) indent(prefix, raw []byte) {
+	if p.err != nil || len(prefix) == 0 {
+		p.write(raw)
+		return
+	}
+	p.buf = p.buf[:0]
+	for len(raw) > 0 {
+		i := bytes.IndexAny(raw, "\r\n") + 1
+		if i == 0 {
+			i = len(raw)
+		}
+		p.buf = append(p.buf, prefix...)
+		p.buf = append(p.buf, raw[:i]...)
+		raw = raw[i:]
+	}
+	p.write(p.buf)
+}
+
+// write writes b as it is, but for the line endings when crlf is set.
+func (p *printer) write(b []byte) {
+	if p.err != nil || len(b) == 0 {
+		return
+	}
+	if p.crlf {
+		b = crlf(b)
+	}
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.err = err
+}
@@ -2,42 +2,135 @@ package nest
 
 import (
 	"io"
+	"sync"
 )
 
 // A SimpleWriter represents an active nestable simplified writer.
 // Each write operation makes a single call to
 // the Writer's Write method.
+// Since writes do not return errors, the first one encountered is recorded
+// by the SimpleWriter and by all its parents, and is reported by Err.
 // A SimpleWriter can be used simultaneously from multiple goroutines;
 // it guarantees to serialize access to the buffer.
 type SimpleWriter struct {
 	Writer *Writer
+
+	parent   *SimpleWriter
+	mutex    sync.Mutex
+	err      error
+	children []*SimpleWriter
 }
 
 // NewSimpleWriter creates a new SimpleWriter with a inner Writer.
-func NewSimpleWriter() *SimpleWriter {
+func NewSimpleWriter(opts ...Option) *SimpleWriter {
 	return &SimpleWriter{
-		Writer: New(),
+		Writer: New(opts...),
 	}
 }
 
-// Child creates a new SimpleWriter from the current one.
-func (s *SimpleWriter) Child(str string) *SimpleWriter {
+// NewSimpleStream creates a new SimpleWriter with a inner streaming Writer,
+// which writes its content through to w as NewStream does.
+func NewSimpleStream(w io.Writer, opts ...Option) *SimpleWriter {
 	return &SimpleWriter{
-		Writer: WithTitledParent(s.Writer, []byte(str)),
+		Writer: NewStream(w, opts...),
 	}
 }
 
+// Child creates a new SimpleWriter from the current one.
+func (s *SimpleWriter) Child(str string, opts ...Option) *SimpleWriter {
+	child := &SimpleWriter{
+		Writer: WithTitledParent(s.Writer, []byte(str), opts...),
+		parent: s,
+	}
+	s.mutex.Lock()
+	s.children = append(s.children, child)
+	s.mutex.Unlock()
+	return child
+}
+
 // Write wraps a call to a Writer.WriteString.
+// A failure is recorded and reported by Err.
 func (s *SimpleWriter) Write(str string) {
-	_, _ = s.Writer.WriteString(str)
+	if _, err := s.Writer.WriteString(str); err != nil {
+		s.fail(err)
+	}
+}
+
+// Err returns the first error encountered while writing
+// to the SimpleWriter or to any of its children, if any.
+func (s *SimpleWriter) Err() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.err
+}
+
+// fail records err in s and in its parents, unless they already failed.
+func (s *SimpleWriter) fail(err error) {
+	for ; s != nil; s = s.parent {
+		s.mutex.Lock()
+		if s.err == nil {
+			s.err = err
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// Flush wraps a call to the inner Writer's Flush method.
+func (s *SimpleWriter) Flush() error {
+	return s.Writer.Flush()
 }
 
-// WriteTo wraps a call to the inner Writer's WriteTo method.
+// Close wraps a call to the inner Writer's Close method.
+func (s *SimpleWriter) Close() error {
+	return s.Writer.Close()
+}
+
+// Done wraps a call to the inner Writer's Done method.
+func (s *SimpleWriter) Done() <-chan struct{} {
+	return s.Writer.Done()
+}
+
+// WriteTo wraps a call to the inner Writer's WriteTo method,
+// so the content is not consumed and can be written more than once.
 // Once the data on the SimpleWriter is fully written,
 // then the data of each Children is gonna be written
 // The return value n is the number of bytes written; it always fits into an
 // int, but it is int64 to match the io.WriterTo interface. Any error
 // encountered during the write is also returned.
+// If a write to the SimpleWriter or to any of its children failed,
+// nothing is written and the error reported by Err is returned.
 func (s *SimpleWriter) WriteTo(w io.Writer) (n int64, err error) {
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
 	return s.Writer.WriteTo(w)
 }
+
+// Drain wraps a call to the inner Writer's Drain method.
+// As WriteTo, it returns the error reported by Err without writing anything.
+func (s *SimpleWriter) Drain(w io.Writer) (n int64, err error) {
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	return s.Writer.Drain(w)
+}
+
+// Reset wraps a call to the inner Writer's Reset method,
+// and forgets the errors recorded by the SimpleWriter and its children,
+// so that the tree can be written again.
+func (s *SimpleWriter) Reset() {
+	s.clear()
+	s.Writer.Reset()
+}
+
+// clear forgets the errors recorded by s and its children.
+func (s *SimpleWriter) clear() {
+	s.mutex.Lock()
+	s.err = nil
+	children := append([]*SimpleWriter(nil), s.children...)
+	s.mutex.Unlock()
+
+	for _, child := range children {
+		child.clear()
+	}
+}
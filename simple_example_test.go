@@ -1,6 +1,7 @@
 package nest
 
 import (
+	"fmt"
 	"os"
 	"sync"
 )
@@ -165,3 +166,89 @@ func ExampleSimpleWriter_WriteTo() {
 
 	// Output:
 }
+
+func ExampleSimpleWriter_Drain() {
+	n := NewSimpleWriter()
+	n.Write("first")
+	if _, err := n.Drain(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	n.Write("second")
+	if _, err := n.Drain(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// first
+	// second
+}
+
+func ExampleSimpleWriter_Reset() {
+	n := NewSimpleWriter()
+	n.Write("discarded")
+	n.Reset()
+
+	n.Write("line one")
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// line one
+}
+
+func ExampleNewSimpleStream() {
+	n := NewSimpleStream(os.Stdout)
+	first := n.Child("First job")
+	second := n.Child("Second job")
+
+	second.Write("done")
+	if err := second.Close(); err != nil {
+		panic(err)
+	}
+	first.Write("done")
+	if err := first.Close(); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// First job
+	//     done
+	// Second job
+	//     done
+}
+
+func ExampleSimpleWriter_Close() {
+	n := NewSimpleWriter()
+	child := n.Child("Section 1")
+	child.Write("content")
+	if err := child.Close(); err != nil {
+		panic(err)
+	}
+	if err := n.Close(); err != nil {
+		panic(err)
+	}
+
+	<-n.Done()
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// Section 1
+	//     content
+}
+
+func ExampleSimpleWriter_Err() {
+	n := NewSimpleWriter()
+	child := n.Child("Section 1")
+	if err := child.Close(); err != nil {
+		panic(err)
+	}
+	child.Write("too late")
+
+	fmt.Println(n.Err())
+	// Output:
+	// nest: Writer is closed
+}
@@ -9,7 +9,7 @@ import (
 
 func TestNewSimpleWriter(t *testing.T) {
 	type want struct {
-		depth    uint8
+		depth    int
 		children int
 	}
 
@@ -110,22 +110,22 @@ func TestSimple_WriteTo(t *testing.T) {
 	}{
 		"single simple": {
 			41,
-			&SimpleWriter{&Writer{
+			&SimpleWriter{Writer: &Writer{
 				Buf: bytes.NewBuffer([]byte("this is the content\n that I want to print")),
 			},
 			}},
 		"simple with two depth": {
 			26,
-			&SimpleWriter{&Writer{
+			&SimpleWriter{Writer: &Writer{
 				Buf: bytes.NewBuffer([]byte("one\n")),
 				Children: []*Writer{
 					{
 						Depth: 1,
-						Buf:   bytes.NewBuffer([]byte("    two\n")),
+						Buf:   bytes.NewBuffer([]byte("two\n")),
 						Children: []*Writer{
 							{
 								Depth: 2,
-								Buf:   bytes.NewBuffer([]byte("        three\n")),
+								Buf:   bytes.NewBuffer([]byte("three\n")),
 							},
 						},
 					},
@@ -169,3 +169,74 @@ func TestSimpleRaceConditions(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestSimple_Err(t *testing.T) {
+	n := NewSimpleWriter()
+	a := n.Child("a")
+	b := n.Child("b")
+	b1 := b.Child("b1")
+
+	n.Write("ok")
+	if err := n.Err(); err != nil {
+		t.Errorf("could not write without errors: %s", err)
+	}
+
+	_ = b1.Close()
+	b1.Write("closed")
+	_ = b.Close()
+	b.Write("closed")
+
+	tests := map[string]struct {
+		simple *SimpleWriter
+		want   error
+	}{
+		"failed writer":            {simple: b1, want: ErrClosed},
+		"parent of failed writer":  {simple: b, want: ErrClosed},
+		"root of failed writer":    {simple: n, want: ErrClosed},
+		"sibling of failed parent": {simple: a, want: nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.simple.Err(); err != test.want {
+				t.Error("could not match error")
+				t.Errorf("got: %v", err)
+				t.Errorf("want: %v", test.want)
+			}
+		})
+	}
+
+	w := &bytes.Buffer{}
+	if i, err := n.WriteTo(w); err != ErrClosed || i != 0 || w.Len() != 0 {
+		t.Errorf("could not return the recorded error on WriteTo: %d %v", i, err)
+	}
+	if i, err := n.Drain(w); err != ErrClosed || i != 0 || w.Len() != 0 {
+		t.Errorf("could not return the recorded error on Drain: %d %v", i, err)
+	}
+}
+
+func TestSimple_Reset_err(t *testing.T) {
+	s := NewSimpleWriter()
+	ch := s.Child("child")
+	_ = ch.Close()
+	ch.Write("late")
+	if err := s.Err(); err != ErrClosed {
+		t.Fatalf("could not record the error: %v", err)
+	}
+
+	s.Reset()
+	for name, simple := range map[string]*SimpleWriter{"root": s, "child": ch} {
+		if err := simple.Err(); err != nil {
+			t.Errorf("could not clear the error of the %s: %s", name, err)
+		}
+	}
+
+	s.Write("fresh")
+	w := &bytes.Buffer{}
+	if _, err := s.WriteTo(w); err != nil {
+		t.Errorf("could not write a reset tree: %s", err)
+	}
+	if want := "fresh\nchild\n"; w.String() != want {
+		t.Errorf("could not match content written: got %q, want %q", w.String(), want)
+	}
+}
@@ -0,0 +1,138 @@
+package nest
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// A stream writes the content of a tree of Writers to an io.Writer,
+// as soon as the position of the content in the tree is final.
+type stream struct {
+	mutex sync.Mutex
+	w     io.Writer
+	color bool
+	crlf  bool
+	err   error
+
+	// buf is reused to format the content written through,
+	// so that writing it does not allocate.
+	buf []byte
+}
+
+// A segment is either some formatted content or a child,
+// waiting for the content preceding it to be written.
+type segment struct {
+	p     []byte
+	child *Writer
+}
+
+// NewStream creates a new Writer which writes its content through to w instead of buffering it.
+// Its children inherit the stream: their content is written as soon as its position is final,
+// that is when the parent is being written and all the earlier siblings are closed,
+// and it is only buffered while it waits for them.
+// Content written to a Writer after one of its children was created
+// is written once that child is closed, keeping the order the content was written in.
+//
+// The content written through is not retained,
+// so WriteTo, Drain and the renderers only see the titles of a streaming Writer.
+func NewStream(w io.Writer, opts ...Option) *Writer {
+	n := New(opts...)
+	n.stream = &stream{w: w, color: n.opts.colored(w), crlf: n.opts.crlf}
+	n.live = true
+	return n
+}
+
+// write formats p indented by prefix with format, and writes it through if nothing precedes it,
+// otherwise it queues a copy of it.
+func (s *stream) write(n *Writer, p, prefix []byte, format func(dst, p, prefix []byte) []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n.ended {
+		return 0, ErrClosed
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.buf = format(s.buf[:0], p, prefix)
+	if len(s.buf) == 0 {
+		return 0, nil
+	}
+	if n.live && len(n.pending) == 0 {
+		return s.emit(s.buf)
+	}
+	n.pending = append(n.pending, segment{p: append([]byte(nil), s.buf...)})
+	return len(s.buf), nil
+}
+
+// add queues the child, which is written once the content of parent preceding it is.
+func (s *stream) add(parent, child *Writer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	parent.pending = append(parent.pending, segment{child: child})
+	s.advance(parent)
+}
+
+// close marks n as ended and writes the content which was waiting for it.
+func (s *stream) close(n *Writer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n.ended = true
+	for ; n != nil; n = n.parent {
+		s.advance(n)
+	}
+	return s.err
+}
+
+// advance writes the queued content of the live n,
+// until it reaches a child which is not closed yet.
+func (s *stream) advance(n *Writer) {
+	for n.live && len(n.pending) > 0 {
+		if child := n.pending[0].child; child != nil {
+			if !child.live {
+				child.live = true
+				child.outline = n.outline
+				if len(child.Title) > 0 {
+					// Numbered when written, the titles below untitled children follow the order they are written in.
+					scope := n.scope()
+					scope.titled++
+					child.outline = append(scope.outline[:len(scope.outline):len(scope.outline)], scope.titled)
+				}
+				title := confineStyles(bytes.Join(child.numberedTitle(child.outline), []byte{'\n'}))
+				if s.color {
+					_, style := child.styles(child.Depth)
+					title = style.apply(title)
+				}
+				s.buf = appendFormat(s.buf[:0], title, child.opts.titlePrefix(child.Depth))
+				_, _ = s.emit(s.buf)
+				s.advance(child)
+			}
+			if !child.ended || len(child.pending) > 0 {
+				return
+			}
+		} else {
+			_, _ = s.emit(n.pending[0].p)
+		}
+		n.pending[0] = segment{}
+		n.pending = n.pending[1:]
+	}
+	if len(n.pending) == 0 {
+		n.pending = nil
+	}
+}
+
+// emit writes p to the underlying io.Writer, recording the first error.
+func (s *stream) emit(p []byte) (int, error) {
+	if s.err != nil || len(p) == 0 {
+		return 0, s.err
+	}
+	if s.crlf {
+		p = crlf(p)
+	}
+	i, err := s.w.Write(p)
+	s.err = err
+	return i, err
+}
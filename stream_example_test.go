@@ -0,0 +1,34 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleNewStream() {
+	base := NewStream(os.Stdout)
+
+	first := WithTitledParent(base, []byte("First job"))
+	second := WithTitledParent(base, []byte("Second job"))
+
+	// The second job is buffered until the first one is closed.
+	if _, err := second.WriteString("done"); err != nil {
+		panic(err)
+	}
+	if err := second.Close(); err != nil {
+		panic(err)
+	}
+
+	// The first job is written as soon as it is written to.
+	if _, err := first.WriteString("done"); err != nil {
+		panic(err)
+	}
+	if err := first.Close(); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// First job
+	//     done
+	// Second job
+	//     done
+}
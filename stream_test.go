@@ -0,0 +1,188 @@
+package nest
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestNewStream(t *testing.T) {
+	w := &bytes.Buffer{}
+	root := NewStream(w)
+
+	steps := []struct {
+		name string
+		do   func()
+		want string
+	}{
+		{
+			name: "root content is written through",
+			do:   func() { _, _ = root.WriteString("root") },
+			want: "root\n",
+		},
+		{
+			name: "first child title is written through",
+			do:   func() { _ = WithTitledParent(root, []byte("a")) },
+			want: "root\na\n",
+		},
+		{
+			name: "first child content is written through",
+			do:   func() { _, _ = root.Children[0].WriteString("a1") },
+			want: "root\na\n    a1\n",
+		},
+		{
+			name: "second child waits for the first one",
+			do:   func() { _, _ = WithTitledParent(root, []byte("b")).WriteString("b1") },
+			want: "root\na\n    a1\n",
+		},
+		{
+			name: "root content waits for the children created before it",
+			do:   func() { _, _ = root.WriteString("root again") },
+			want: "root\na\n    a1\n",
+		},
+		{
+			name: "closing the first child writes the second one",
+			do:   func() { _ = root.Children[0].Close() },
+			want: "root\na\n    a1\nb\n    b1\n",
+		},
+		{
+			name: "second child content is written through",
+			do:   func() { _, _ = root.Children[1].WriteString("b2") },
+			want: "root\na\n    a1\nb\n    b1\n    b2\n",
+		},
+		{
+			name: "closing the second child writes the root content",
+			do:   func() { _ = root.Children[1].Close() },
+			want: "root\na\n    a1\nb\n    b1\n    b2\nroot again\n",
+		},
+		{
+			name: "root content is written through again",
+			do:   func() { _, _ = root.WriteString("end") },
+			want: "root\na\n    a1\nb\n    b1\n    b2\nroot again\nend\n",
+		},
+	}
+
+	for _, step := range steps {
+		step.do()
+		if w.String() != step.want {
+			t.Errorf("%s: could not match streamed content", step.name)
+			t.Errorf("got: %q", w.String())
+			t.Errorf("want: %q", step.want)
+		}
+	}
+}
+
+func TestNewStream_nested(t *testing.T) {
+	w := &bytes.Buffer{}
+	root := NewStream(w)
+	a := WithTitledParent(root, []byte("a"))
+	a1 := WithTitledParent(a, []byte("a1"))
+	b := WithTitledParent(root, []byte("b"))
+	_, _ = b.WriteString("b")
+	a2 := WithTitledParent(a, []byte("a2"))
+	_, _ = a2.WriteString("a2")
+
+	_ = a2.Close()
+	_ = a.Close()
+	if want := "a\n    a1\n"; w.String() != want {
+		t.Errorf("could not wait for the open grandchild: got %q, want %q", w.String(), want)
+	}
+
+	_ = a1.Close()
+	if want := "a\n    a1\n    a2\n        a2\nb\n    b\n"; w.String() != want {
+		t.Errorf("could not write the closed siblings: got %q, want %q", w.String(), want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failure")
+}
+
+func TestNewStream_numbered(t *testing.T) {
+	build := func(n *Writer) {
+		a := WithTitledParent(n, []byte("a"))
+		untitled := WithParent(a)
+		b := WithTitledParent(a, []byte("a.b"))
+		ua := WithTitledParent(untitled, []byte("u.a"))
+		_, _ = ua.WriteString("content")
+		_ = ua.Close()
+		_ = untitled.Close()
+		_, _ = b.WriteString("content")
+		_ = b.Close()
+		_ = a.Close()
+		_ = n.Close()
+	}
+
+	w := &bytes.Buffer{}
+	build(NewStream(w, Numbered()))
+	want := "1 a\n        1.1 u.a\n            content\n    1.2 a.b\n        content\n"
+	if w.String() != want {
+		t.Error("could not match numbered content streamed")
+		t.Errorf("got: %q", w.String())
+		t.Errorf("want: %q", want)
+	}
+
+	n := New(Numbered())
+	build(n)
+	rendered := &bytes.Buffer{}
+	if _, err := n.WriteTo(rendered); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if rendered.String() != want {
+		t.Error("could not match numbered content rendered")
+		t.Errorf("got: %q", rendered.String())
+		t.Errorf("want: %q", want)
+	}
+}
+
+func TestNewStream_depthCollapse(t *testing.T) {
+	w := &bytes.Buffer{}
+	n := NewStream(w, MaxDepth(1, DepthCollapse))
+	a := WithTitledParent(n, []byte("a"))
+	_, _ = WithTitledParent(a, []byte("deep")).WriteString("content")
+
+	want := "a\n    deep\n        content\n"
+	if w.String() != want {
+		t.Error("could not match the deeper Writers streamed in full")
+		t.Errorf("got: %q", w.String())
+		t.Errorf("want: %q", want)
+	}
+}
+
+func TestNewStream_error(t *testing.T) {
+	root := NewStream(failingWriter{})
+	if _, err := root.WriteString("root"); err == nil {
+		t.Error("could not return the write error")
+	}
+	if _, err := WithParent(root).WriteString("child"); err == nil {
+		t.Error("could not return the previous write error")
+	}
+	if err := root.Close(); err == nil {
+		t.Error("could not return the write error on close")
+	}
+}
+
+func TestStreamRaceConditions(t *testing.T) {
+	if !raceEnabled {
+		t.Skip("race detector is not enabled")
+	}
+
+	const pool = 10_000
+	base := NewStream(ioutil.Discard)
+	wg := sync.WaitGroup{}
+	wg.Add(pool)
+	for i := 1; i <= pool; i++ {
+		go func(i int) {
+			child := WithParent(base)
+			_, _ = child.WriteString("hello")
+			_, _ = base.WriteString("hello")
+			_ = child.Close()
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,138 @@
+package nest
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// A Style is an ANSI text style, as the parameters of a Select Graphic Rendition escape sequence,
+// such as "1" for bold or "38;5;208" for an orange foreground.
+// Styles are only applied by the text renderers, WriteTo, Drain, Tree and streaming Writers,
+// and never stored in the buffer of a Writer.
+type Style string
+
+// The styles supported by most terminals.
+const (
+	Bold      Style = "1"
+	Faint     Style = "2"
+	Italic    Style = "3"
+	Underline Style = "4"
+	Black     Style = "30"
+	Red       Style = "31"
+	Green     Style = "32"
+	Yellow    Style = "33"
+	Blue      Style = "34"
+	Magenta   Style = "35"
+	Cyan      Style = "36"
+	White     Style = "37"
+)
+
+// And returns the combination of the two styles, such as Bold.And(Red).
+func (s Style) And(other Style) Style {
+	switch {
+	case s == "":
+		return other
+	case other == "":
+		return s
+	default:
+		return s + ";" + other
+	}
+}
+
+// apply returns raw with each of its non empty lines enclosed in the escape sequences
+// setting and resetting the style, so that it never bleeds into the indentation.
+func (s Style) apply(raw []byte) []byte {
+	if s == "" {
+		return raw
+	}
+	var styled []byte
+	for len(raw) > 0 {
+		line, end := raw, len(raw)
+		if i := bytes.IndexByte(raw, '\n'); i >= 0 {
+			line, end = raw[:i], i+1
+		}
+		if len(line) > 0 {
+			styled = append(styled, "\x1b["...)
+			styled = append(styled, s...)
+			styled = append(styled, 'm')
+			styled = append(styled, line...)
+			styled = append(styled, "\x1b[0m"...)
+		}
+		styled = append(styled, raw[len(line):end]...)
+		raw = raw[end:]
+	}
+	return styled
+}
+
+// A ColorMode tells when the text renderers apply the styles.
+type ColorMode int
+
+const (
+	// ColorAuto applies the styles when writing to a terminal, unless the NO_COLOR environment variable is set.
+	ColorAuto ColorMode = iota
+	// ColorAlways applies the styles whatever the output is.
+	ColorAlways
+	// ColorNever never applies the styles.
+	ColorNever
+)
+
+// Color sets when the styles are applied, the default being ColorAuto.
+func Color(mode ColorMode) Option {
+	return func(n *Writer) {
+		n.opts.color = mode
+	}
+}
+
+// Palette styles the Writers depending on their Depth, the first style being used
+// for the root, the following ones for the deeper levels, starting again from the first
+// once all are used.
+func Palette(styles ...Style) Option {
+	return func(n *Writer) {
+		n.opts.palette = styles
+	}
+}
+
+// TitleStyle sets the style of the titles, which is combined with the one of their Writer.
+func TitleStyle(s Style) Option {
+	return func(n *Writer) {
+		n.opts.titleStyle = s
+	}
+}
+
+// Styled overrides the Palette for this section only, such as Red for a section reporting an error:
+// its content is rendered with s, and its title with s combined with the TitleStyle,
+// while its Children keep the color of their depth.
+func Styled(s Style) Option {
+	return func(n *Writer) {
+		n.style = s
+	}
+}
+
+// styles returns the styles of the content and of the title of n at the given depth.
+func (n *Writer) styles(depth int) (content, title Style) {
+	content = n.style
+	if content == "" && len(n.opts.palette) > 0 {
+		content = n.opts.palette[depth%len(n.opts.palette)]
+	}
+	return content, n.opts.titleStyle.And(content)
+}
+
+// colored reports whether the styles are applied to the output written to w.
+func (o options) colored(w io.Writer) bool {
+	switch o.color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
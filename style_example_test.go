@@ -0,0 +1,30 @@
+package nest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func ExampleStyled() {
+	n := New(Color(ColorAlways), TitleStyle(Bold))
+	if _, err := WithTitledParent(n, []byte("TestA")).WriteString("ok"); err != nil {
+		panic(err)
+	}
+	if _, err := WithTitledParent(n, []byte("TestB"), Styled(Red)).WriteString("FAIL"); err != nil {
+		panic(err)
+	}
+
+	w := &bytes.Buffer{}
+	if _, err := n.WriteTo(w); err != nil {
+		panic(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n") {
+		fmt.Printf("%q\n", line)
+	}
+	// Output:
+	// "\x1b[1mTestA\x1b[0m"
+	// "    ok"
+	// "\x1b[1;31mTestB\x1b[0m"
+	// "    \x1b[31mFAIL\x1b[0m"
+}
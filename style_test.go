@@ -0,0 +1,121 @@
+package nest
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestStyle_And(t *testing.T) {
+	tests := map[string]struct {
+		style Style
+		want  Style
+	}{
+		"both":         {style: Bold.And(Red), want: "1;31"},
+		"empty first":  {style: Style("").And(Red), want: "31"},
+		"empty second": {style: Bold.And(""), want: "1"},
+		"empty":        {style: Style("").And(""), want: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.style != test.want {
+				t.Errorf("could not match style: got %q, want %q", test.style, test.want)
+			}
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	build := func(opts ...Option) *Writer {
+		n := New(opts...)
+		_, _ = n.WriteString("root")
+		ok := WithTitledParent(n, []byte("ok"))
+		_, _ = ok.WriteString("fine\n\ntoo")
+		failed := WithTitledParent(n, []byte("failed"), Styled(Red))
+		_, _ = failed.WriteString("error")
+		_, _ = WithParent(failed).WriteString("nested")
+		return n
+	}
+
+	tests := map[string]struct {
+		nest *Writer
+		want string
+	}{
+		"node style": {
+			nest: build(Color(ColorAlways)),
+			want: "root\nok\n    fine\n    \n    too\n\x1b[31mfailed\x1b[0m\n    \x1b[31merror\x1b[0m\n        nested\n",
+		},
+		"never": {
+			nest: build(Color(ColorNever), TitleStyle(Bold), Palette(Blue)),
+			want: "root\nok\n    fine\n    \n    too\nfailed\n    error\n        nested\n",
+		},
+		"title and node style": {
+			nest: build(Color(ColorAlways), TitleStyle(Bold)),
+			want: "root\n\x1b[1mok\x1b[0m\n    fine\n    \n    too\n\x1b[1;31mfailed\x1b[0m\n    \x1b[31merror\x1b[0m\n        nested\n",
+		},
+		"palette": {
+			nest: build(Color(ColorAlways), Palette(Blue, Green)),
+			want: "\x1b[34mroot\x1b[0m\n\x1b[32mok\x1b[0m\n    \x1b[32mfine\x1b[0m\n    \n    \x1b[32mtoo\x1b[0m\n\x1b[31mfailed\x1b[0m\n    \x1b[31merror\x1b[0m\n        \x1b[34mnested\x1b[0m\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if _, err := test.nest.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+			if bytes.Contains(test.nest.Buf.Bytes(), []byte{'\x1b'}) {
+				t.Error("could not keep the buffer free of escape sequences")
+			}
+		})
+	}
+}
+
+func TestStyle_tree(t *testing.T) {
+	n := New(Color(ColorAlways), TitleStyle(Bold))
+	_, _ = WithTitledParent(n, []byte("failed"), Styled(Red)).WriteString("error")
+
+	w := &bytes.Buffer{}
+	if _, err := (Tree{Writer: n}).WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "\x1b[1;31mfailed\x1b[0m\n└── \x1b[31merror\x1b[0m\n"; w.String() != want {
+		t.Errorf("could not match tree: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestStyle_stream(t *testing.T) {
+	w := &bytes.Buffer{}
+	n := NewStream(w, Color(ColorAlways), TitleStyle(Bold))
+	failed := WithTitledParent(n, []byte("failed"), Styled(Red))
+	_, _ = failed.WriteString("error")
+	_ = failed.Close()
+
+	if want := "\x1b[1;31mfailed\x1b[0m\n    \x1b[31merror\x1b[0m\n"; w.String() != want {
+		t.Errorf("could not match stream: got %q, want %q", w.String(), want)
+	}
+}
+
+func TestColor_auto(t *testing.T) {
+	o := options{}
+	if o.colored(&bytes.Buffer{}) {
+		t.Error("could not disable the styles for a buffer")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("could not create a pipe: %s", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if o.colored(w) {
+		t.Error("could not disable the styles for a pipe")
+	}
+}
@@ -0,0 +1,50 @@
+package nest
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// ExpandTabs makes the text renderers, WriteTo, Drain, Tree and streaming Writers,
+// replace the tabs with spaces up to the next tab stop, every width columns.
+// The columns are counted from the start of the content, after the indentation,
+// so that tables such as the ones written by text/tabwriter keep their alignment at any depth.
+// A width lower than one keeps the tabs.
+func ExpandTabs(width int) Option {
+	return func(n *Writer) {
+		n.opts.tabWidth = width
+	}
+}
+
+// expandTabs returns raw with its tabs expanded as set with ExpandTabs,
+// raw itself when there is nothing to expand.
+func (o options) expandTabs(raw []byte) []byte {
+	if o.tabWidth <= 0 || bytes.IndexByte(raw, '\t') < 0 {
+		return raw
+	}
+
+	var expanded []byte
+	column := 0
+	for len(raw) > 0 {
+		if l := escapeLen(raw); l > 0 {
+			expanded = append(expanded, raw[:l]...)
+			raw = raw[l:]
+			continue
+		}
+		r, size := utf8.DecodeRune(raw)
+		switch r {
+		case '\t':
+			spaces := o.tabWidth - column%o.tabWidth
+			expanded = append(expanded, bytes.Repeat([]byte{' '}, spaces)...)
+			column += spaces
+		case '\n', '\r':
+			expanded = append(expanded, raw[:size]...)
+			column = 0
+		default:
+			expanded = append(expanded, raw[:size]...)
+			column += runeWidth(r)
+		}
+		raw = raw[size:]
+	}
+	return expanded
+}
@@ -0,0 +1,30 @@
+package nest
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func ExampleExpandTabs() {
+	n := New(ExpandTabs(8), LineBuffered())
+	report := WithTitledParent(WithTitledParent(n, []byte("coverage")), []byte("packages"))
+
+	tw := tabwriter.NewWriter(report, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "package\tcoverage")
+	fmt.Fprintln(tw, "github.com/damianopetrungaro/nest\t92.1%")
+	fmt.Fprintln(tw, "github.com/damianopetrungaro/nest/cmd\t40.0%")
+	if err := tw.Flush(); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// coverage
+	//     packages
+	//         package                                 coverage
+	//         github.com/damianopetrungaro/nest       92.1%
+	//         github.com/damianopetrungaro/nest/cmd   40.0%
+}
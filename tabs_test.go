@@ -0,0 +1,78 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		s    string
+		want string
+	}{
+		"kept": {
+			s:    "a\tb",
+			want: "        a\tb\n",
+		},
+		"expanded from the content column": {
+			opts: []Option{ExpandTabs(4)},
+			s:    "a\tb\nabcd\te\n\tf",
+			want: "        a   b\n        abcd    e\n            f\n",
+		},
+		"wide characters": {
+			opts: []Option{ExpandTabs(4)},
+			s:    "日本\tx\nab\tx",
+			want: "        日本    x\n        ab  x\n",
+		},
+		"escape sequences": {
+			opts: []Option{ExpandTabs(4)},
+			s:    "\x1b[1mab\x1b[0m\tx",
+			want: "        \x1b[1mab\x1b[0m  x\n",
+		},
+		"carriage return": {
+			opts: []Option{ExpandTabs(4)},
+			s:    "abc\r\tx",
+			want: "        abc\r            x\n",
+		},
+		"wrapped after expansion": {
+			opts: []Option{ExpandTabs(8), Wrap(22)},
+			s:    "key\tvalue\tmore",
+			want: "        key     value\n        more\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := WithParent(WithParent(New(test.opts...)))
+			if _, err := n.WriteString(test.s); err != nil {
+				t.Fatalf("could not write: %s", err)
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+			if !bytes.Equal(bytes.TrimSuffix(n.Buf.Bytes(), []byte{'\n'}), []byte(test.s)) {
+				t.Errorf("could not keep the tabs in the buffer: %q", n.Buf.String())
+			}
+		})
+	}
+}
+
+func TestExpandTabs_tree(t *testing.T) {
+	n := New(ExpandTabs(4))
+	_, _ = WithTitledParent(n, []byte("title")).WriteString("a\tb")
+
+	w := &bytes.Buffer{}
+	if _, err := (Tree{Writer: n}).WriteTo(w); err != nil {
+		t.Errorf("could not write: %s", err)
+	}
+	if want := "title\n└── a   b\n"; w.String() != want {
+		t.Errorf("could not match tree: got %q, want %q", w.String(), want)
+	}
+}
@@ -0,0 +1,112 @@
+package nest
+
+import (
+	"io"
+)
+
+// Glyphs are the connectors a Tree draws its branches with.
+type Glyphs struct {
+	// Branch precedes an entry followed by a sibling.
+	Branch string
+	// Last precedes the last entry of a level.
+	Last string
+	// Vertical continues a branch alongside the entries nested below it.
+	Vertical string
+	// Space replaces Vertical below the last entry of a level.
+	Space string
+}
+
+var (
+	// UnicodeGlyphs draw a tree with box-drawing characters.
+	UnicodeGlyphs = Glyphs{Branch: "├── ", Last: "└── ", Vertical: "│   ", Space: "    "}
+	// ASCIIGlyphs draw a tree with plain ASCII characters.
+	ASCIIGlyphs = Glyphs{Branch: "|-- ", Last: "`-- ", Vertical: "|   ", Space: "    "}
+)
+
+// A Tree renders a Writer drawing connectors in place of the indentation,
+// as the tree command does.
+// The lines written to a Writer and the titles of its Children are the entries of a level;
+// untitled Children do not add a level, their lines and the titles of their Children
+// being entries of the level of their parent.
+// which of them is the last one is only known at render time,
+// so the glyphs are never stored in the buffer of the Writer.
+// A zero Glyphs draws the tree with the UnicodeGlyphs.
+type Tree struct {
+	Writer *Writer
+	Glyphs Glyphs
+}
+
+// WriteTo draws the Writer and its Children to w without consuming their content.
+// The return value n is the number of bytes written; it always fits into an
+// int, but it is int64 to match the io.WriterTo interface. Any error
+// encountered during the write is also returned.
+func (t Tree) WriteTo(w io.Writer) (n int64, err error) {
+	if t.Glyphs == (Glyphs{}) {
+		t.Glyphs = UnicodeGlyphs
+	}
+
+	p := t.Writer.printer(w)
+	v := t.Writer.view()
+	_, style := v.styles(p)
+	for _, title := range v.terminalTitle() {
+		p.line(style.apply(title))
+	}
+	t.entries(p, v, nil, len(v.title) == 0)
+	return p.n, p.err
+}
+
+// An entry of a Tree is either a styled line or a titled child, heading the level below it.
+type entry struct {
+	line  []byte
+	child *view
+}
+
+// level appends to entries the ones of the level of v:
+// its lines, then its titled children and the entries of its untitled ones in order.
+func (t Tree) level(p *printer, v *view, entries []entry) []entry {
+	content, _ := v.styles(p)
+	for _, line := range v.terminalLines() {
+		entries = append(entries, entry{line: content.apply(line)})
+	}
+	for _, child := range v.children {
+		if len(child.title) == 0 {
+			entries = t.level(p, child, entries)
+			continue
+		}
+		entries = append(entries, entry{child: child})
+	}
+	return entries
+}
+
+// entries draws the entries of the level of v below prefix.
+// The entries of the top level are drawn without connectors.
+func (t Tree) entries(p *printer, v *view, prefix []byte, top bool) {
+	entries := t.level(p, v, nil)
+	for i, e := range entries {
+		branch, indent := t.connectors(i == len(entries)-1, top)
+		if e.child == nil {
+			p.line(prefix, branch, e.line)
+			continue
+		}
+		_, title := e.child.styles(p)
+		for j, line := range e.child.terminalTitle() {
+			if j > 0 {
+				branch = indent
+			}
+			p.line(prefix, branch, title.apply(line))
+		}
+		t.entries(p, e.child, append(prefix[:len(prefix):len(prefix)], indent...), false)
+	}
+}
+
+// connectors returns the glyph preceding an entry and the one continuing below it.
+func (t Tree) connectors(last, top bool) (branch, indent []byte) {
+	switch {
+	case top:
+		return nil, nil
+	case last:
+		return []byte(t.Glyphs.Last), []byte(t.Glyphs.Space)
+	default:
+		return []byte(t.Glyphs.Branch), []byte(t.Glyphs.Vertical)
+	}
+}
@@ -0,0 +1,57 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleTree() {
+	base := New()
+
+	orderedList := WithTitledParent(base, []byte("This is the start of the ordered list"))
+
+	one := WithTitledParent(orderedList, []byte("1. Item one"))
+	if _, err := one.Write([]byte("1.1 Written item")); err != nil {
+		panic(err)
+	}
+
+	two := WithTitledParent(orderedList, []byte("2. Item two"))
+	if _, err := two.Write([]byte("2.1 Written item\n2.2 Written item")); err != nil {
+		panic(err)
+	}
+
+	unorderedList := WithTitledParent(base, []byte("This is the start of the unordered list"))
+	if _, err := unorderedList.Write([]byte("- Item one\n- Item two")); err != nil {
+		panic(err)
+	}
+
+	if _, err := (Tree{Writer: base}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// This is the start of the ordered list
+	// ├── 1. Item one
+	// │   └── 1.1 Written item
+	// └── 2. Item two
+	//     ├── 2.1 Written item
+	//     └── 2.2 Written item
+	// This is the start of the unordered list
+	// ├── - Item one
+	// └── - Item two
+}
+
+func ExampleTree_ascii() {
+	base := NewSimpleWriter()
+
+	section := base.Child("Section")
+	section.Write("content")
+	section.Child("Sub section").Write("nested content")
+
+	if _, err := (Tree{Writer: section.Writer, Glyphs: ASCIIGlyphs}).WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Section
+	// |-- content
+	// `-- Sub section
+	//     `-- nested content
+}
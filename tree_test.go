@@ -0,0 +1,71 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTree_WriteTo(t *testing.T) {
+	titled := New()
+	a := WithTitledParent(titled, []byte("a"))
+	_, _ = a.WriteString("a1")
+	b := WithTitledParent(a, []byte("b\nsecond line"))
+	_, _ = b.WriteString("b1")
+	_, _ = WithTitledParent(a, []byte("c")).WriteString("c1")
+
+	untitled := New()
+	_, _ = untitled.WriteString("root")
+	_, _ = WithParent(untitled).WriteString("one\ntwo")
+	_, _ = WithTitledParent(untitled, []byte("last")).WriteString("three")
+
+	nested := WithTitledParent(New(), []byte("R"))
+	parent := WithTitledParent(nested, []byte("P"))
+	_, _ = WithParent(parent).WriteString("x")
+	_ = WithTitledParent(nested, []byte("Q"))
+
+	tests := map[string]struct {
+		tree Tree
+		want string
+	}{
+		"titled children with multi-line title": {
+			tree: Tree{Writer: titled},
+			want: "a\n├── a1\n├── b\n│   second line\n│   └── b1\n└── c\n    └── c1\n",
+		},
+		"titled sub tree with ascii glyphs": {
+			tree: Tree{Writer: b, Glyphs: ASCIIGlyphs},
+			want: "b\nsecond line\n`-- b1\n",
+		},
+		"untitled child": {
+			tree: Tree{Writer: untitled},
+			want: "root\none\ntwo\nlast\n└── three\n",
+		},
+		"untitled child of a titled parent": {
+			tree: Tree{Writer: nested},
+			want: "R\n├── P\n│   └── x\n└── Q\n",
+		},
+		"empty writer": {
+			tree: Tree{Writer: New()},
+			want: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			i, err := test.tree.WriteTo(w)
+			if err != nil {
+				t.Errorf("could not write tree to the writer: %s", err)
+			}
+			if i != int64(len(test.want)) {
+				t.Error("could not match written bytes")
+				t.Errorf("got: %d", i)
+				t.Errorf("want: %d", len(test.want))
+			}
+			if w.String() != test.want {
+				t.Error("could not match tree written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package nest
+
+import (
+	"errors"
+)
+
+// SkipSubtree is used as a return value from a WalkFunc called before the children of a Writer
+// to indicate that they are to be skipped. It is not returned as an error by any function.
+var SkipSubtree = errors.New("skip this subtree")
+
+// A WalkFunc is the type of the function called by Walk to visit each Writer.
+// The path holds the ancestors of node, from the Writer Walk was called on to the parent of node;
+// it is reused by Walk, so it must not be retained.
+type WalkFunc func(path []*Writer, node *Writer) error
+
+// Walk walks the tree rooted at n, visiting the children of each Writer in the order they are rendered.
+// The pre function is called before visiting the children of a Writer, and may return SkipSubtree
+// to skip them; the post function is called after. Either of them can be nil.
+// Any other error stops the walk and is returned.
+//
+// The Children of each Writer are read holding its lock, which is released before
+// calling the functions, so they are free to write to the visited Writers or to create children.
+func (n *Writer) Walk(pre, post WalkFunc) error {
+	return n.walk(nil, pre, post)
+}
+
+func (n *Writer) walk(path []*Writer, pre, post WalkFunc) error {
+	var err error
+	if pre != nil {
+		err = pre(path, n)
+	}
+	if err != nil && err != SkipSubtree {
+		return err
+	}
+
+	if err == nil {
+		path = append(path, n)
+		for _, child := range n.ordered() {
+			if err := child.walk(path, pre, post); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+	}
+
+	if post != nil {
+		if err := post(path, n); err != SkipSubtree {
+			return err
+		}
+	}
+	return nil
+}
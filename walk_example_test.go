@@ -0,0 +1,37 @@
+package nest
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ExampleWriter_Walk() {
+	base := New()
+	tests := WithTitledParent(base, []byte("Tests"))
+	if _, err := WithTitledParent(tests, []byte("TestA")).WriteString("ok"); err != nil {
+		panic(err)
+	}
+	if _, err := WithTitledParent(tests, []byte("TestB")).WriteString("fail\nexpected 1, got 2"); err != nil {
+		panic(err)
+	}
+	if _, err := WithTitledParent(base, []byte("Skipped")).WriteString("not visited"); err != nil {
+		panic(err)
+	}
+
+	err := base.Walk(func(path []*Writer, node *Writer) error {
+		if string(node.Title) == "Skipped" {
+			return SkipSubtree
+		}
+		if len(node.Title) > 0 {
+			fmt.Printf("%s%s: %d lines\n", strings.Repeat("> ", len(path)-1), node.Title, len(node.Lines()))
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// Tests: 0 lines
+	// > TestA: 1 lines
+	// > TestB: 2 lines
+}
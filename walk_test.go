@@ -0,0 +1,124 @@
+package nest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWriter_Walk(t *testing.T) {
+	n := New(SortBy(ByTitle))
+	b := WithTitledParent(n, []byte("b"))
+	_ = WithTitledParent(b, []byte("b1"))
+	a := WithTitledParent(n, []byte("a"))
+	_ = WithTitledParent(a, []byte("a1"))
+
+	failure := errors.New("failure")
+	name := func(path []*Writer, node *Writer) string {
+		if node == n {
+			return "/"
+		}
+		s := ""
+		for _, p := range path {
+			s += string(p.Title) + "/"
+		}
+		return s + string(node.Title)
+	}
+
+	tests := map[string]struct {
+		pre   func(path []*Writer, node *Writer) error
+		post  bool
+		want  []string
+		error error
+	}{
+		"pre order": {
+			want: []string{"pre /", "pre /a", "pre /a/a1", "pre /b", "pre /b/b1"},
+		},
+		"pre and post order": {
+			post: true,
+			want: []string{"pre /", "pre /a", "pre /a/a1", "post /a/a1", "post /a", "pre /b", "pre /b/b1", "post /b/b1", "post /b", "post /"},
+		},
+		"skip subtree": {
+			pre: func(path []*Writer, node *Writer) error {
+				if node == a {
+					return SkipSubtree
+				}
+				return nil
+			},
+			post: true,
+			want: []string{"pre /", "pre /a", "post /a", "pre /b", "pre /b/b1", "post /b/b1", "post /b", "post /"},
+		},
+		"stop on error": {
+			pre: func(path []*Writer, node *Writer) error {
+				if node == a {
+					return failure
+				}
+				return nil
+			},
+			post:  true,
+			want:  []string{"pre /", "pre /a"},
+			error: failure,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var got []string
+			pre := func(path []*Writer, node *Writer) error {
+				got = append(got, fmt.Sprintf("pre %s", name(path, node)))
+				if test.pre != nil {
+					return test.pre(path, node)
+				}
+				return nil
+			}
+			var post WalkFunc
+			if test.post {
+				post = func(path []*Writer, node *Writer) error {
+					got = append(got, fmt.Sprintf("post %s", name(path, node)))
+					return nil
+				}
+			}
+
+			if err := n.Walk(pre, post); err != test.error {
+				t.Errorf("could not match error: got %v, want %v", err, test.error)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Error("could not match visited writers")
+				t.Errorf("got: %v", got)
+				t.Errorf("want: %v", test.want)
+			}
+		})
+	}
+}
+
+func TestWriter_Walk_write(t *testing.T) {
+	n := New()
+	_ = WithParent(n)
+
+	err := n.Walk(func(path []*Writer, node *Writer) error {
+		_, err := node.WriteString("visited")
+		if len(path) == 0 {
+			_ = WithParent(node)
+		}
+		return err
+	}, nil)
+	if err != nil {
+		t.Errorf("could not walk: %s", err)
+	}
+	for i, child := range n.Children {
+		if i == 0 && child.Buf.String() != "visited\n" {
+			t.Errorf("could not write while walking: %q", child.Buf.String())
+		}
+	}
+}
+
+func TestWriter_Lines(t *testing.T) {
+	n := WithParent(New())
+	_, _ = n.WriteString("one\ntwo")
+
+	want := [][]byte{[]byte("one"), []byte("two")}
+	if got := n.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("could not match lines: got %q, want %q", got, want)
+	}
+}
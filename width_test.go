@@ -0,0 +1,33 @@
+package nest
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want int
+	}{
+		"ascii":               {s: "hello", want: 5},
+		"latin accents":       {s: "héllo wörld", want: 11},
+		"combining accent":    {s: "he\u0301llo", want: 5},
+		"east asian wide":     {s: "日本語", want: 6},
+		"fullwidth":           {s: "ＡＢ", want: 4},
+		"hangul":              {s: "한국어", want: 6},
+		"emoji":               {s: "🚀🎉", want: 4},
+		"emoji with modifier": {s: "👍🏽", want: 2},
+		"variation selector":  {s: "✔️", want: 1},
+		"control characters":  {s: "a\x00b\x1b", want: 2},
+		"escape sequences":    {s: "\x1b[1;31mred\x1b[0m", want: 3},
+		"invalid utf8":        {s: "a\xffb", want: 3},
+		"box drawing":         {s: "├── ", want: 4},
+		"mixed":               {s: "go 言語 🚀", want: 10},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := width([]byte(test.s)); got != test.want {
+				t.Errorf("could not match width of %q: got %d, want %d", test.s, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package nest
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Wrap word-wraps the lines longer than width columns, minus the indentation of the Writer,
+// when they are written by WriteTo, Drain or a streaming Writer,
+// the continuation lines being indented as the Writer is.
+// The width is measured in terminal columns, wide characters such as CJK ideographs
+// and emoji taking two of them; words longer than a line are broken where they overflow it.
+// A width lower than one disables wrapping, as does an indentation leaving no room for the content.
+func Wrap(width int) Option {
+	return func(n *Writer) {
+		n.opts.wrap = width
+	}
+}
+
+// HangingIndent sets the string added to the indentation of the continuation lines
+// of the lines wrapped with Wrap.
+func HangingIndent(s string) Option {
+	return func(n *Writer) {
+		n.opts.hanging = []byte(s)
+	}
+}
+
+// wrapLines returns raw with the lines longer than the wrap width, once indented by prefix,
+// split in several lines, the continuation lines starting with the hanging indentation.
+// raw is returned as it is when it does not need to be wrapped.
+func (o options) wrapLines(raw, prefix []byte) []byte {
+	if o.wrap <= 0 {
+		return raw
+	}
+	first := o.wrap - width(prefix)
+	rest := first - width(o.hanging)
+	if rest <= 0 || width(raw) <= first {
+		return raw
+	}
+
+	var wrapped []byte
+	for len(raw) > 0 {
+		line, end := raw, len(raw)
+		if i := bytes.IndexByte(raw, '\n'); i >= 0 {
+			line, end = raw[:i], i+1
+		}
+		for i, segment := range wrap(line, first, rest) {
+			if i > 0 {
+				wrapped = append(wrapped, '\n')
+				wrapped = append(wrapped, o.hanging...)
+			}
+			wrapped = append(wrapped, segment...)
+		}
+		wrapped = append(wrapped, raw[len(line):end]...)
+		raw = raw[end:]
+	}
+	return wrapped
+}
+
+// wrap splits line in segments, the first one fitting first columns and the others rest columns.
+func wrap(line []byte, first, rest int) [][]byte {
+	var segments [][]byte
+	for limit := first; width(line) > limit; limit = rest {
+		cut, next := breakAt(line, limit)
+		segments = append(segments, line[:cut])
+		line = line[next:]
+	}
+	return append(segments, line)
+}
+
+// breakAt returns where line is cut to fit limit columns, at the last space
+// preceding the overflowing character if any, and where the following segment starts.
+func breakAt(line []byte, limit int) (cut, next int) {
+	space, w := -1, 0
+	for i := 0; i < len(line); {
+		if l := escapeLen(line[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRune(line[i:])
+		if r == ' ' && i > 0 && line[i-1] != ' ' {
+			space = i
+		}
+		if w += runeWidth(r); w > limit {
+			if space > 0 && isWordStart(line, space) {
+				next = space
+				for next < len(line) && line[next] == ' ' {
+					next++
+				}
+				return space, next
+			}
+			if i == 0 {
+				return size, size
+			}
+			return i, i
+		}
+		i += size
+	}
+	return len(line), len(line)
+}
+
+// isWordStart reports whether line holds more than spaces before i.
+func isWordStart(line []byte, i int) bool {
+	return len(bytes.TrimLeft(line[:i], " ")) > 0
+}
@@ -0,0 +1,23 @@
+package nest
+
+import (
+	"os"
+)
+
+func ExampleWrap() {
+	n := New(Wrap(32), HangingIndent("  "))
+	test := WithTitledParent(n, []byte("TestParse"))
+	if _, err := test.WriteString("expected the input to be parsed as a tree, but the second line is indented by three spaces"); err != nil {
+		panic(err)
+	}
+
+	if _, err := n.WriteTo(os.Stdout); err != nil {
+		panic(err)
+	}
+	// Output:
+	// TestParse
+	//     expected the input to be
+	//       parsed as a tree, but the
+	//       second line is indented by
+	//       three spaces
+}
@@ -0,0 +1,106 @@
+package nest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := map[string]struct {
+		opts []Option
+		s    string
+		want string
+	}{
+		"short line": {
+			opts: []Option{Wrap(20)},
+			s:    "short line",
+			want: "    short line\n",
+		},
+		"words": {
+			opts: []Option{Wrap(20)},
+			s:    "the quick brown fox jumps over the lazy dog",
+			want: "    the quick brown\n    fox jumps over\n    the lazy dog\n",
+		},
+		"hanging indent": {
+			opts: []Option{Wrap(20), HangingIndent("  ")},
+			s:    "the quick brown fox jumps over the lazy dog",
+			want: "    the quick brown\n      fox jumps over\n      the lazy dog\n",
+		},
+		"several lines": {
+			opts: []Option{Wrap(14)},
+			s:    "short\nthe quick brown fox",
+			want: "    short\n    the quick\n    brown fox\n",
+		},
+		"long word": {
+			opts: []Option{Wrap(10)},
+			s:    "abcdefghijklmn",
+			want: "    abcdef\n    ghijkl\n    mn\n",
+		},
+		"spaces at the break": {
+			opts: []Option{Wrap(10)},
+			s:    "abc    def ghi",
+			want: "    abc\n    def\n    ghi\n",
+		},
+		"leading spaces": {
+			opts: []Option{Wrap(10)},
+			s:    "    abcdefgh",
+			want: "        ab\n    cdefgh\n",
+		},
+		"wide characters": {
+			opts: []Option{Wrap(10)},
+			s:    "日本語のテキスト",
+			want: "    日本語\n    のテキ\n    スト\n",
+		},
+		"emoji": {
+			opts: []Option{Wrap(10)},
+			s:    "ok 🚀🚀 done",
+			want: "    ok\n    🚀🚀\n    done\n",
+		},
+		"no room for the content": {
+			opts: []Option{Wrap(4)},
+			s:    "not wrapped",
+			want: "    not wrapped\n",
+		},
+		"disabled": {
+			opts: []Option{Wrap(0)},
+			s:    "the quick brown fox jumps over the lazy dog",
+			want: "    the quick brown fox jumps over the lazy dog\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := WithParent(New(test.opts...))
+			if _, err := n.WriteString(test.s); err != nil {
+				t.Fatalf("could not write: %s", err)
+			}
+			w := &bytes.Buffer{}
+			if _, err := n.WriteTo(w); err != nil {
+				t.Errorf("could not write: %s", err)
+			}
+			if w.String() != test.want {
+				t.Error("could not match content written")
+				t.Errorf("got: %q", w.String())
+				t.Errorf("want: %q", test.want)
+			}
+		})
+	}
+}
+
+func TestWrap_stream(t *testing.T) {
+	w := &bytes.Buffer{}
+	n := NewStream(w, Wrap(20))
+	child := WithTitledParent(n, []byte("title"))
+	if _, err := child.WriteString("the quick brown fox jumps over the lazy dog"); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	if err := child.Close(); err != nil {
+		t.Fatalf("could not close: %s", err)
+	}
+
+	if want := "title\n    the quick brown\n    fox jumps over\n    the lazy dog\n"; w.String() != want {
+		t.Error("could not match content written")
+		t.Errorf("got: %q", w.String())
+		t.Errorf("want: %q", want)
+	}
+}